@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// aliasPrefix 为调用别名时使用的前缀，如 ais @deploy-logs
+const aliasPrefix = "@"
+
+// placeholderPattern 匹配别名提示中的位置参数占位符，如 {1}
+var placeholderPattern = regexp.MustCompile(`\{(\d+)\}`)
+
+var (
+	aliasCmd = &cobra.Command{
+		Use:   "alias",
+		Short: "别名管理命令",
+		Long: `管理保存的提示，保存后可以通过 ais @名称 [参数...] 调用。
+
+提示中可以使用 {1}、{2} 等占位符引用调用时传入的位置参数。`,
+	}
+
+	aliasAddCmd = &cobra.Command{
+		Use:   "add [NAME] [PROMPT]",
+		Short: "添加别名",
+		Long:  `添加或覆盖一个别名，例如: ais alias add logs "显示 {1} 服务最近 200 行带时间戳的日志"`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runAliasAdd,
+	}
+
+	aliasListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出别名",
+		Long:  `列出所有别名及其上次执行的命令。`,
+		Args:  cobra.NoArgs,
+		RunE:  runAliasList,
+	}
+
+	aliasRemoveCmd = &cobra.Command{
+		Use:   "rm [NAME]",
+		Short: "删除别名",
+		Long:  `删除指定的别名。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAliasRemove,
+	}
+
+	aliasPinCmd = &cobra.Command{
+		Use:   "pin [NAME]",
+		Short: "固定别名的命令",
+		Long:  `固定别名上次选中执行的命令，之后使用相同参数调用时直接执行该命令，不再请求模型。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAliasPin,
+	}
+
+	aliasUnpinCmd = &cobra.Command{
+		Use:   "unpin [NAME]",
+		Short: "取消固定别名的命令",
+		Long:  `取消固定别名的命令，之后调用时重新请求模型。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAliasUnpin,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasAddCmd, aliasListCmd, aliasRemoveCmd, aliasPinCmd, aliasUnpinCmd)
+}
+
+// isAliasCall 判断参数是否为别名调用
+func isAliasCall(args []string) bool {
+	return len(args) > 0 && strings.HasPrefix(args[0], aliasPrefix) && len(args[0]) > len(aliasPrefix)
+}
+
+// runAlias 执行别名：固定了命令且参数相同时直接执行，否则展开提示后请求模型
+func runAlias(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], aliasPrefix)
+	aliasArgs := args[1:]
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	alias, err := cfg.GetAlias(name)
+	if err != nil {
+		return err
+	}
+
+	var selectedCmd string
+	if pinned := alias.PinnedCommand(aliasArgs); pinned != "" {
+		slog.Debug("使用别名固定的命令", "name", name, "command", pinned)
+		fmt.Println(i18n.T("使用固定的命令:"))
+		selectedCmd, err = chooseCommand([]candidate{{Command: pinned}})
+		if err != nil || selectedCmd == "" {
+			return err
+		}
+		err = runShellCommand(selectedCmd)
+	} else {
+		description, expandErr := expandAlias(alias.Prompt, aliasArgs)
+		if expandErr != nil {
+			return expandErr
+		}
+		slog.Debug("别名展开完成", "name", name, "description", description)
+		selectedCmd, err = translateAndRun(cmd, description)
+	}
+
+	if selectedCmd != "" {
+		if saveErr := cfg.SetAliasLastCommand(name, aliasArgs, selectedCmd); saveErr != nil {
+			slog.Warn(i18n.Sprintf("记录别名 %s 的命令失败: %v", name, saveErr))
+		}
+	}
+	return err
+}
+
+// expandAlias 将提示中的 {n} 替换为第 n 个位置参数，未被引用的参数追加到提示末尾
+func expandAlias(prompt string, args []string) (string, error) {
+	used := make([]bool, len(args))
+	var expandErr error
+
+	expanded := placeholderPattern.ReplaceAllStringFunc(prompt, func(match string) string {
+		index, _ := strconv.Atoi(placeholderPattern.FindStringSubmatch(match)[1])
+		if index < 1 || index > len(args) {
+			if expandErr == nil {
+				expandErr = i18n.Errorf("缺少别名参数 %s", match)
+			}
+			return match
+		}
+		used[index-1] = true
+		return args[index-1]
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+
+	var extra []string
+	for i, arg := range args {
+		if !used[i] {
+			extra = append(extra, arg)
+		}
+	}
+	if len(extra) > 0 {
+		expanded += " " + strings.Join(extra, " ")
+	}
+	return expanded, nil
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	name := strings.TrimPrefix(args[0], aliasPrefix)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return i18n.Errorf("无效的别名名称: %s", args[0])
+	}
+
+	if err := cfg.AddAlias(name, args[1]); err != nil {
+		return i18n.Errorf("添加别名失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已添加别名 %s%s = %s\n"), aliasPrefix, name, args[1])
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if len(cfg.Aliases) == 0 {
+		fmt.Println(i18n.T("还没有别名，使用 ais alias add 添加"))
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		alias := cfg.Aliases[name]
+		fmt.Printf("%s%s: %s\n", aliasPrefix, name, alias.Prompt)
+		if alias.LastCommand == "" {
+			continue
+		}
+		if alias.Pinned {
+			fmt.Printf(i18n.T("    固定命令: %s\n"), alias.LastCommand)
+		} else {
+			fmt.Printf(i18n.T("    上次命令: %s\n"), alias.LastCommand)
+		}
+	}
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	name := strings.TrimPrefix(args[0], aliasPrefix)
+	if err := cfg.RemoveAlias(name); err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.T("已删除别名 %s%s\n"), aliasPrefix, name)
+	return nil
+}
+
+func runAliasPin(cmd *cobra.Command, args []string) error {
+	return setAliasPinned(args[0], true)
+}
+
+func runAliasUnpin(cmd *cobra.Command, args []string) error {
+	return setAliasPinned(args[0], false)
+}
+
+func setAliasPinned(name string, pinned bool) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	name = strings.TrimPrefix(name, aliasPrefix)
+	if err := cfg.PinAlias(name, pinned); err != nil {
+		return err
+	}
+
+	if pinned {
+		fmt.Printf(i18n.T("已固定别名 %s%s 的命令: %s\n"), aliasPrefix, name, cfg.Aliases[name].LastCommand)
+	} else {
+		fmt.Printf(i18n.T("已取消固定别名 %s%s 的命令\n"), aliasPrefix, name)
+	}
+	return nil
+}
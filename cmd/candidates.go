@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/system"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	candidateCount   int
+	minCandidates    int
+	maxCandidates    int
+	diverse          bool
+	sortByConfidence bool
+)
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.IntVarP(&candidateCount, "candidates", "n", 0, "恰好返回的候选命令数，等同于同时设置最少和最多候选命令数")
+	flags.IntVar(&minCandidates, "min-candidates", 0, "最少返回的候选命令数")
+	flags.IntVar(&maxCandidates, "max-candidates", 0, "最多返回的候选命令数")
+	flags.BoolVar(&diverse, "diverse", false, "要求候选命令使用不同的工具或写法")
+	flags.BoolVar(&sortByConfidence, "sort-confidence", false, "按模型给出的置信度排序候选命令")
+}
+
+// applyCandidateFlags 用命令行标志覆盖配置中的候选命令设置
+func applyCandidateFlags(cmd *cobra.Command, cfg *config.Config) {
+	flags := cmd.Flags()
+	if flags.Changed("min-candidates") {
+		cfg.MinCandidates = minCandidates
+	}
+	if flags.Changed("max-candidates") {
+		cfg.MaxCandidates = maxCandidates
+	}
+	if flags.Changed("candidates") {
+		cfg.MinCandidates = candidateCount
+		cfg.MaxCandidates = candidateCount
+	}
+	if flags.Changed("diverse") {
+		cfg.Diverse = diverse
+	}
+	if flags.Changed("sort-confidence") {
+		cfg.SortByConfidence = sortByConfidence
+	}
+}
+
+// candidate 为展示给用户选择的一条候选命令
+type candidate struct {
+	Command    string
+	Confidence *float64 // 模型给出的置信度，未给出时为 nil
+	Missing    []string // 命令用到但未安装的可执行文件
+}
+
+// label 返回展示在候选命令后面的附加信息
+func (c candidate) label() string {
+	var parts []string
+	if c.Confidence != nil {
+		parts = append(parts, fmt.Sprintf("(%.2f)", *c.Confidence))
+	}
+	if len(c.Missing) > 0 {
+		parts = append(parts, i18n.Sprintf("[未安装: %s]", strings.Join(c.Missing, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildCandidates 去除空白规范化后重复的候选命令，按需根据置信度排序，
+// 截断到配置的最多候选命令数，并将用到未安装工具的命令排到最后
+func buildCandidates(aiResp *AIResponse, cfg *config.Config) []candidate {
+	hasConfidence := len(aiResp.Confidence) == len(aiResp.Command)
+
+	seen := make(map[string]bool)
+	var candidates []candidate
+	for i, command := range aiResp.Command {
+		normalized := strings.Join(strings.Fields(command), " ")
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		item := candidate{Command: strings.TrimSpace(command)}
+		if hasConfidence {
+			item.Confidence = &aiResp.Confidence[i]
+		}
+		candidates = append(candidates, item)
+	}
+
+	if cfg.SortByConfidence && hasConfidence {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return *candidates[i].Confidence > *candidates[j].Confidence
+		})
+	}
+
+	_, max := cfg.CandidateRange()
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+
+	for i := range candidates {
+		candidates[i].Missing = system.MissingCommands(candidates[i].Command)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].Missing) == 0 && len(candidates[j].Missing) > 0
+	})
+
+	return candidates
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/system"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	collectorCmd = &cobra.Command{
+		Use:   "collector",
+		Short: "上下文收集器管理命令",
+		Long: `管理发送给模型的上下文收集器。
+
+每个收集器负责一类上下文信息，例如 shell、系统信息、目录内容等，
+可以单独启用或禁用，并设置输出的 token 预算。
+也可以添加外部收集器，外部收集器通过 sh -c 执行脚本，并将标准输出作为上下文。`,
+	}
+
+	collectorListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出收集器",
+		Long:  `列出所有收集器及其启用状态和 token 预算。`,
+		Args:  cobra.NoArgs,
+		RunE:  runCollectorList,
+	}
+
+	collectorEnableCmd = &cobra.Command{
+		Use:   "enable [NAME]",
+		Short: "启用收集器",
+		Long:  `启用指定的收集器。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runCollectorEnable,
+	}
+
+	collectorDisableCmd = &cobra.Command{
+		Use:   "disable [NAME]",
+		Short: "禁用收集器",
+		Long:  `禁用指定的收集器。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runCollectorDisable,
+	}
+
+	collectorBudgetCmd = &cobra.Command{
+		Use:   "budget [NAME] [TOKENS]",
+		Short: "设置收集器的 token 预算",
+		Long:  `设置收集器输出的 token 预算，超出部分会被截断。`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runCollectorBudget,
+	}
+
+	collectorTimeoutCmd = &cobra.Command{
+		Use:   "timeout [NAME] [MILLISECONDS]",
+		Short: "设置收集器的超时时间",
+		Long:  `设置收集器的超时时间（毫秒），超时的收集器会被跳过，不会阻塞请求。默认超时时间可以通过 ais config set collector-timeout 设置。`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runCollectorTimeout,
+	}
+
+	collectorAddCmd = &cobra.Command{
+		Use:   "add [NAME] [COMMAND]",
+		Short: "添加外部收集器",
+		Long:  `添加外部收集器，例如: ais collector add k8s "kubectl config current-context"`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runCollectorAdd,
+	}
+
+	collectorRemoveCmd = &cobra.Command{
+		Use:   "rm [NAME]",
+		Short: "删除外部收集器",
+		Long:  `删除指定的外部收集器。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runCollectorRemove,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(collectorCmd)
+	collectorCmd.AddCommand(collectorListCmd, collectorEnableCmd, collectorDisableCmd,
+		collectorBudgetCmd, collectorTimeoutCmd, collectorAddCmd, collectorRemoveCmd)
+}
+
+// findCollector 查找内置或外部收集器的注册项
+func findCollector(cfg *config.Config, name string) (system.Registration, error) {
+	for _, registration := range system.Registrations(cfg) {
+		if registration.Collector.Name() == name {
+			return registration, nil
+		}
+	}
+	return system.Registration{}, i18n.Errorf("收集器不存在: %s", name)
+}
+
+func runCollectorList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	for _, registration := range system.Registrations(cfg) {
+		enabled, budget := registration.Settings(cfg)
+
+		status := i18n.T("已启用")
+		if !enabled {
+			status = i18n.T("已禁用")
+		}
+		kind := i18n.T("内置")
+		if registration.External {
+			kind = i18n.T("外部")
+		}
+		fmt.Printf(i18n.T("%-10s %s  %s  预算 %d tokens  超时 %v\n"),
+			registration.Collector.Name(), kind, status, budget, registration.Timeout(cfg))
+	}
+	return nil
+}
+
+func runCollectorEnable(cmd *cobra.Command, args []string) error {
+	return setCollectorEnabled(args[0], true)
+}
+
+func runCollectorDisable(cmd *cobra.Command, args []string) error {
+	return setCollectorEnabled(args[0], false)
+}
+
+func setCollectorEnabled(name string, enabled bool) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	if _, err := findCollector(cfg, name); err != nil {
+		return err
+	}
+
+	if err := cfg.SetCollectorEnabled(name, enabled); err != nil {
+		return i18n.Errorf("设置收集器失败: %v", err)
+	}
+
+	if enabled {
+		fmt.Printf(i18n.T("已启用收集器 %s\n"), name)
+	} else {
+		fmt.Printf(i18n.T("已禁用收集器 %s\n"), name)
+	}
+	return nil
+}
+
+func runCollectorBudget(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	if _, err := findCollector(cfg, args[0]); err != nil {
+		return err
+	}
+
+	budget, err := strconv.Atoi(args[1])
+	if err != nil || budget < 1 {
+		return i18n.Errorf("token 预算必须是正整数: %s", args[1])
+	}
+
+	if err := cfg.SetCollectorBudget(args[0], budget); err != nil {
+		return i18n.Errorf("设置收集器失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置收集器 %s 的预算 = %d tokens\n"), args[0], budget)
+	return nil
+}
+
+func runCollectorTimeout(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	if _, err := findCollector(cfg, args[0]); err != nil {
+		return err
+	}
+
+	timeout, err := strconv.Atoi(args[1])
+	if err != nil || timeout < 1 {
+		return i18n.Errorf("超时时间必须是正整数: %s", args[1])
+	}
+
+	if err := cfg.SetCollectorTimeout(args[0], timeout); err != nil {
+		return i18n.Errorf("设置收集器失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置收集器 %s 的超时时间 = %d ms\n"), args[0], timeout)
+	return nil
+}
+
+func runCollectorAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	if registration, err := findCollector(cfg, args[0]); err == nil && !registration.External {
+		return i18n.Errorf("不能覆盖内置收集器: %s", args[0])
+	}
+
+	if err := cfg.AddExternalCollector(args[0], args[1]); err != nil {
+		return i18n.Errorf("添加外部收集器失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已添加外部收集器 %s = %s\n"), args[0], args[1])
+	return nil
+}
+
+func runCollectorRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if err := cfg.RemoveExternalCollector(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.T("已删除外部收集器 %s\n"), args[0])
+	return nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/mockserver"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	devCmd = &cobra.Command{
+		Use:   "dev",
+		Short: "开发辅助命令",
+		Long:  `用于开发和测试 AI-Shell 的辅助命令。`,
+	}
+
+	devMockServerCmd = &cobra.Command{
+		Use:   "mock-server",
+		Short: "启动本地模拟 API 服务",
+		Long: `启动兼容 OpenAI /v1/chat/completions 的本地模拟服务，按规则文件回复请求，
+可以注入延迟、错误状态码、格式错误的 JSON 和流式响应，用于离线测试各种错误处理。
+
+规则按顺序用正则表达式匹配用户的请求，第一条匹配的规则生效。没有指定规则文件时使用内置的示例规则，
+可以通过 --print-rules 输出示例规则作为编写规则文件的起点。规则文件在每次请求时重新读取。
+
+启动后运行 ais config set url http://127.0.0.1:8080/v1/chat/completions 即可使用。`,
+		Args: cobra.NoArgs,
+		RunE: runDevMockServer,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+	devCmd.AddCommand(devMockServerCmd)
+	devMockServerCmd.Flags().String("addr", "127.0.0.1:8080", "监听地址")
+	devMockServerCmd.Flags().String("rules", "", "规则文件路径，为空时使用内置的示例规则")
+	devMockServerCmd.Flags().Bool("print-rules", false, "输出内置的示例规则后退出")
+}
+
+func runDevMockServer(cmd *cobra.Command, args []string) error {
+	if printRules, _ := cmd.Flags().GetBool("print-rules"); printRules {
+		fmt.Print(mockserver.ExampleRules)
+		return nil
+	}
+	addr, _ := cmd.Flags().GetString("addr")
+	rulesPath, _ := cmd.Flags().GetString("rules")
+
+	handler, err := mockserver.New(rulesPath)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return i18n.Errorf("监听 %s 失败: %v", addr, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Handler: handler}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf(i18n.T("模拟服务已启动: http://%s/v1/chat/completions\n"), listener.Addr())
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return i18n.Errorf("模拟服务异常退出: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"AI-Shell/internal/cassette"
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/openai"
+)
+
+// explainRequestError 为发送请求失败的错误附加可操作的提示
+func explainRequestError(err error, cfg *config.Config) error {
+	if hint := requestErrorHint(err, cfg); hint != "" {
+		return i18n.Errorf("%v\n提示: %s", err, hint)
+	}
+	return i18n.Errorf("发送请求失败: %v", err)
+}
+
+func requestErrorHint(err error, cfg *config.Config) string {
+	var (
+		authErr          *openai.AuthError
+		quotaErr         *openai.QuotaError
+		rateLimitErr     *openai.RateLimitError
+		modelNotFoundErr *openai.ModelNotFoundError
+		contextLengthErr *openai.ContextLengthError
+		contentFilterErr *openai.ContentFilterError
+		serverErr        *openai.ServerError
+		noMatchErr       *cassette.NoMatchError
+		certErr          *tls.CertificateVerificationError
+		opErr            *net.OpError
+		urlErr           *url.Error
+	)
+
+	switch {
+	case errors.As(err, &noMatchErr):
+		return i18n.Sprintf("请求与录制时不同，请使用 %s=record:目录 重新录制", cassette.EnvVar)
+	case errors.As(err, &authErr):
+		return i18n.T("API 密钥无效或没有权限，请运行 ais config set key 设置正确的密钥")
+	case errors.As(err, &quotaErr):
+		return i18n.T("账户额度已用尽，请检查账户的余额和账单设置")
+	case errors.As(err, &rateLimitErr):
+		return i18n.T("请求过于频繁，请稍后再试，或运行 ais config set retries 增加重试次数")
+	case errors.As(err, &modelNotFoundErr):
+		return i18n.Sprintf("模型 %s 在 %s 上不可用，请运行 ais models --pick 选择可用的模型", cfg.Model, cfg.URL)
+	case errors.As(err, &contextLengthErr):
+		return i18n.T("请求超出了模型的上下文长度，请使用 ais collector disable 或 ais collector budget 减少发送的上下文")
+	case errors.As(err, &contentFilterErr):
+		return i18n.T("请求或回复被服务端的内容审核拦截，请换一种描述方式")
+	case errors.As(err, &serverErr):
+		return i18n.T("服务端暂时不可用，请稍后再试")
+	case errors.As(err, &certErr):
+		return i18n.T("服务端证书不受信任，如果服务使用企业 CA 签发的证书，请运行 ais config set ca-file 设置 CA 证书")
+	case strings.Contains(err.Error(), "tls: certificate required"):
+		// 服务端发来的 TLS 警报没有导出的错误类型，只能匹配错误信息
+		return i18n.T("服务端要求客户端证书，请运行 ais config set client-cert 设置客户端证书和私钥")
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		return i18n.T("无法连接到代理，请检查 ais config set proxy 或 HTTPS_PROXY 环境变量设置的代理地址")
+	case errors.As(err, &urlErr):
+		return i18n.Sprintf("无法连接到 %s，请检查网络，或运行 ais config set url 设置正确的地址", cfg.URL)
+	}
+	return ""
+}
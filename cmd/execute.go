@@ -1,25 +1,32 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strconv"
-	"log/slog"
+	"time"
 
 	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
 	"AI-Shell/internal/openai"
+	"AI-Shell/internal/prompt"
+	"AI-Shell/internal/redact"
 	"AI-Shell/internal/system"
+	"AI-Shell/internal/usage"
 
 	"github.com/spf13/cobra"
 )
 
 // AIResponse 表示AI返回的命令选项
 type AIResponse struct {
-	Command []string `json:"command"`
-	Msg     string   `json:"msg"`
-	Code    int      `json:"code"`
+	Command    []string  `json:"command"`
+	Msg        string    `json:"msg"`
+	Code       int       `json:"code"`
+	Confidence []float64 `json:"confidence,omitempty"`
 }
 
 var executeCmd = &cobra.Command{
@@ -35,20 +42,45 @@ func init() {
 }
 
 func runExecute(cmd *cobra.Command, args []string) error {
-	slog.Debug("开始执行 runExecute", "args", args)
+	if isAliasCall(args) {
+		return runAlias(cmd, args)
+	}
+	_, err := translateAndRun(cmd, args[0])
+	return err
+}
+
+// translateAndRun 将自然语言描述翻译为候选命令，并执行用户选中的命令。
+// 返回用户选中的命令，用户选择退出时返回空字符串。
+func translateAndRun(cmd *cobra.Command, description string) (string, error) {
+	slog.Debug("开始执行 translateAndRun", "description", description)
 	// 加载配置
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		slog.Error("加载配置失败", "error", err)
-		return fmt.Errorf("加载配置失败: %v", err)
+		return "", i18n.Errorf("加载配置失败: %v", err)
 	}
 	slog.Debug("配置加载成功", "config", cfg)
 
+	// 查找并合并项目级配置
+	project, err := config.FindProjectConfig(".")
+	if err != nil {
+		slog.Error("加载项目配置失败", "error", err)
+		return "", i18n.Errorf("加载项目配置失败: %v", err)
+	}
+	if project != nil {
+		slog.Debug("项目配置加载成功", "path", project.Path, "project", project)
+	}
+	cfg = cfg.WithProject(project)
+	applyCandidateFlags(cmd, cfg)
+
 	// 获取系统信息
-	sysInfo, err := system.GetSystemInfo()
+	sysInfo, err := system.GetSystemInfo(cmd.Context(), &system.Request{Query: description, Config: cfg})
 	if err != nil {
 		slog.Error("获取系统信息失败", "error", err)
-		return fmt.Errorf("获取系统信息失败: %v", err)
+		return "", i18n.Errorf("获取系统信息失败: %v", err)
+	}
+	if project != nil && project.Context != "" {
+		sysInfo += "\n[项目上下文]\n" + project.Context
 	}
 	slog.Debug("系统信息获取成功", "sysInfo", sysInfo)
 
@@ -56,53 +88,66 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	showData, err := cmd.Flags().GetBool("show-data")
 	if err != nil {
 		slog.Error("获取show-data标志失败", "error", err)
-		return fmt.Errorf("获取show-data标志失败: %v", err)
+		return "", i18n.Errorf("获取show-data标志失败: %v", err)
 	}
 	slog.Debug("获取show-data标志", "showData", showData)
 
 	// 创建OpenAI客户端
-	client := openai.NewClient(cfg)
+	client, err := openai.NewClient(cfg)
+	if err != nil {
+		slog.Error("创建OpenAI客户端失败", "error", err)
+		return "", err
+	}
 	slog.Debug("OpenAI客户端创建成功")
 
-	// 准备系统提示
-	systemPrompt := "你是一个命令行命令翻译机，负责将用户输入翻译为命令行命令，你需要以json方式回复，以下是示例\n" +
-		"{\"command\": [\"ls\"],\"msg\": \"执行此命令将列出当前目录中的文件和子目录。\",\"code\": 0}\n" +
-		"command是可执行命令，可以有多种翻译结果，每一项都是完整的命令，不要把一条命令拆分为开，用户选择其中一条执行，最多为10个，" +
-		"msg是展示给用户的提示信息，code为翻译结果，0为成功翻译，1为不能翻译、缺少信息或其他异常情况。"
+	// 根据提示模板渲染系统提示
+	systemPrompt, err := prompt.Build(cfg, prompt.NewData(cfg, sysInfo))
+	if err != nil {
+		slog.Error("渲染系统提示失败", "error", err)
+		return "", i18n.Errorf("渲染系统提示失败: %v", err)
+	}
 	slog.Debug("系统提示准备完成", "systemPrompt", systemPrompt)
 
+	// 发送前隐藏密钥、主目录、用户名和敏感文件名
+	redactor, err := redact.New(cfg.Redact)
+	if err != nil {
+		slog.Error("创建脱敏规则失败", "error", err)
+		return "", i18n.Errorf("创建脱敏规则失败: %v", err)
+	}
+	// 用户输入的请求只隐藏密钥，文件名和路径保持原样
+	systemPrompt, systemRedacted := redactor.Redact(systemPrompt)
+	sysInfo, infoRedacted := redactor.Redact(sysInfo)
+	description, requestRedacted := redactor.RedactRequest(description)
+	redactedCount := systemRedacted + infoRedacted + requestRedacted
+	slog.Debug("脱敏完成", "count", redactedCount)
+
 	// 构建用户提示（包含系统信息）
-	userPrompt := sysInfo + "\n" + args[0]
+	userPrompt := sysInfo + "\n" + description
 	slog.Debug("用户提示构建完成", "userPrompt", userPrompt)
 
-	var resp *openai.Response
-	var reqResp *openai.RequestResponse
+	// 本月费用达到硬性预算时不再发送请求
+	if err := checkBudget(cfg); err != nil {
+		return "", err
+	}
 
-	// 根据是否需要显示数据选择不同的方法
-	if showData {
-		slog.Debug("使用 SendRequestWithData 发送请求")
-		// 发送请求到OpenAI并获取请求和响应数据
-		reqResp, err = client.SendRequestWithData(systemPrompt, userPrompt)
-		if err != nil {
-			slog.Error("SendRequestWithData 发送请求失败", "error", err)
-			return fmt.Errorf("发送请求失败: %v", err)
-		}
-		resp = reqResp.Response
-		slog.Debug("SendRequestWithData 响应接收成功", "response", resp)
-	} else {
-		slog.Debug("使用 SendRequest 发送请求")
-		// 只发送请求到OpenAI
-		resp, err = client.SendRequest(systemPrompt, userPrompt)
-		if err != nil {
-			slog.Error("SendRequest 发送请求失败", "error", err)
-			return fmt.Errorf("发送请求失败: %v", err)
+	// 发送请求到OpenAI
+	result, err := client.Complete(cmd.Context(), openai.Options{SystemPrompt: systemPrompt, UserPrompt: userPrompt})
+	if err != nil {
+		slog.Error("发送请求失败", "error", err, "attempts", result.Attempts, "latency", result.Latency)
+		if result.Fallback > 0 {
+			// 提示信息针对最后尝试的备用模型
+			return "", explainRequestError(err, cfg.Resolve(config.Fallback{URL: result.URL, Model: result.Request.Model}))
 		}
-		slog.Debug("SendRequest 响应接收成功", "response", resp)
+		return "", explainRequestError(err, cfg)
 	}
+	resp := result.Response
+	trackUsage(cfg, result)
+	slog.Debug("响应接收成功", "model", result.Model, "attempts", result.Attempts, "latency", result.Latency,
+		"promptTokens", result.Usage.PromptTokens, "completionTokens", result.Usage.CompletionTokens)
 
 	if resp == nil || len(resp.Choices) == 0 {
 		slog.Error("未收到有效响应或响应中没有Choices")
-		return fmt.Errorf("未收到有效响应")
+		return "", i18n.Errorf("未收到有效响应")
 	}
 	slog.Debug("OpenAI响应有效", "choicesCount", len(resp.Choices))
 
@@ -132,39 +177,49 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	slog.Debug("准备解析JSON内容", "contentToParse", content)
 	if err := json.Unmarshal([]byte(content), &aiResp); err != nil {
 		slog.Error("解析响应JSON失败", "error", err, "content", content)
-		return fmt.Errorf("解析响应失败: %v", err)
+		return "", i18n.Errorf("解析响应失败: %v", err)
 	}
 	slog.Debug("响应JSON解析成功", "aiResponse", aiResp)
 
+	candidates := buildCandidates(&aiResp, cfg)
+	slog.Debug("候选命令整理完成", "candidates", candidates)
+
 	// 如果showData为true，显示发送和接收的数据
 	if showData {
 		slog.Debug("开始显示发送和接收的数据")
-		fmt.Println("=== 请求和响应数据 ===")
+		fmt.Println(i18n.T("=== 请求和响应数据 ==="))
+		if redactor == nil {
+			fmt.Println(i18n.T("脱敏已禁用"))
+		} else {
+			fmt.Printf(i18n.T("已隐藏 %d 处敏感信息\n"), redactedCount)
+		}
 
 		// 显示发送的数据
-		fmt.Println("发送数据:")
-		requestData, err := json.MarshalIndent(reqResp.Request, "", "  ")
+		fmt.Println(i18n.T("发送数据:"))
+		requestData, err := json.MarshalIndent(result.Request, "", "  ")
 		if err != nil {
 			slog.Error("格式化请求数据失败", "error", err)
-			return fmt.Errorf("格式化请求数据失败: %v", err)
+			return "", i18n.Errorf("格式化请求数据失败: %v", err)
 		}
 		fmt.Println(string(requestData))
 		slog.Debug("请求数据已显示")
 
-		fmt.Println("\n响应数据:")
-		responseData, err := json.MarshalIndent(reqResp.Response, "", "  ")
-		if err != nil {
+		fmt.Println(i18n.T("\n响应数据:"))
+		var responseData bytes.Buffer
+		if err := json.Indent(&responseData, result.Raw, "", "  "); err != nil {
 			slog.Error("格式化响应数据失败", "error", err)
-			return fmt.Errorf("格式化响应数据失败: %v", err)
+			return "", i18n.Errorf("格式化响应数据失败: %v", err)
 		}
-		fmt.Println(string(responseData))
+		fmt.Println(responseData.String())
+		fmt.Printf(i18n.T("模型: %s  耗时: %v  请求次数: %d  令牌: 输入 %d / 输出 %d\n"), result.Model,
+			result.Latency.Round(time.Millisecond), result.Attempts, result.Usage.PromptTokens, result.Usage.CompletionTokens)
 		slog.Debug("响应数据已显示")
 
-		fmt.Println("\n解析后的AI响应:")
+		fmt.Println(i18n.T("\n解析后的AI响应:"))
 		aiRespData, err := json.MarshalIndent(aiResp, "", "  ")
 		if err != nil {
 			slog.Error("格式化AI响应数据失败", "error", err)
-			return fmt.Errorf("格式化AI响应数据失败: %v", err)
+			return "", i18n.Errorf("格式化AI响应数据失败: %v", err)
 		}
 		fmt.Println(string(aiRespData))
 		slog.Debug("解析后的AI响应数据已显示")
@@ -173,26 +228,42 @@ func runExecute(cmd *cobra.Command, args []string) error {
 
 	// 输出提示信息
 	fmt.Println(aiResp.Msg)
+	if result.Fallback > 0 {
+		fmt.Printf(i18n.T("(由备用模型 %s @ %s 回答)\n"), result.Model, usage.Endpoint(result.URL))
+	}
 	fmt.Println("---------------------")
-	fmt.Println("可用的命令选项:")
+	fmt.Println(i18n.T("可用的命令选项:"))
 
 	slog.Debug("输出提示信息", "message", aiResp.Msg)
 	// 检查翻译结果
 	if aiResp.Code != 0 {
 		slog.Error("命令翻译失败", "aiResponseCode", aiResp.Code, "aiResponseMessage", aiResp.Msg)
-		return fmt.Errorf("命令翻译失败: %s (code: %d)", aiResp.Msg, aiResp.Code)
+		return "", i18n.Errorf("命令翻译失败: %s (code: %d)", aiResp.Msg, aiResp.Code)
 	}
 	slog.Debug("命令翻译成功")
 
+	selectedCmd, err := chooseCommand(candidates)
+	if err != nil || selectedCmd == "" {
+		return "", err
+	}
+	return selectedCmd, runShellCommand(selectedCmd)
+}
+
+// chooseCommand 显示候选命令并读取用户选择，用户选择退出时返回空字符串
+func chooseCommand(candidates []candidate) (string, error) {
 	// 显示可用的命令选项
-	slog.Debug("显示可用命令选项", "commands", aiResp.Command)
-	for i, cmd := range aiResp.Command {
-		fmt.Printf("%d: %s\n", i+1, cmd)
+	slog.Debug("显示可用命令选项", "candidates", candidates)
+	for i, candidate := range candidates {
+		if label := candidate.label(); label != "" {
+			fmt.Printf("%d: %s  %s\n", i+1, candidate.Command, label)
+			continue
+		}
+		fmt.Printf("%d: %s\n", i+1, candidate.Command)
 	}
-	fmt.Println("0: 退出")
+	fmt.Println(i18n.T("0: 退出"))
 
 	// 获取用户选择
-	fmt.Print("请选择要执行的命令: ")
+	fmt.Print(i18n.T("请选择要执行的命令: "))
 	var choice string
 	fmt.Scanln(&choice)
 	slog.Debug("用户选择", "choice", choice)
@@ -201,25 +272,29 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	num, err := strconv.Atoi(choice)
 	if err != nil {
 		slog.Error("无效的用户选择，无法转换为数字", "choice", choice, "error", err)
-		return fmt.Errorf("无效的选择")
+		return "", i18n.Errorf("无效的选择")
 	}
 	slog.Debug("用户选择解析为数字", "number", num)
 
 	if num == 0 {
 		slog.Debug("用户选择退出程序")
-		fmt.Println("退出程序。")
-		return nil
+		fmt.Println(i18n.T("退出程序。"))
+		return "", nil
 	}
 
-	if num < 1 || num > len(aiResp.Command) {
-		slog.Error("用户选择的数字超出范围", "number", num, "commandCount", len(aiResp.Command))
-		return fmt.Errorf("无效的选择")
+	if num < 1 || num > len(candidates) {
+		slog.Error("用户选择的数字超出范围", "number", num, "commandCount", len(candidates))
+		return "", i18n.Errorf("无效的选择")
 	}
 
-	// 执行选中的命令
-	selectedCmd := aiResp.Command[num-1]
+	selectedCmd := candidates[num-1].Command
 	slog.Debug("选中的命令", "selectedCmd", selectedCmd)
-	fmt.Printf("执行命令: %s\n", selectedCmd)
+	return selectedCmd, nil
+}
+
+// runShellCommand 在交互式 bash 中执行命令
+func runShellCommand(selectedCmd string) error {
+	fmt.Printf(i18n.T("执行命令: %s\n"), selectedCmd)
 	fmt.Println("---------------------")
 
 	// 设置环境变量
@@ -239,7 +314,7 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	slog.Debug("开始执行命令")
 	if err := command.Run(); err != nil {
 		slog.Error("命令执行失败", "error", err, "command", selectedCmd)
-		return fmt.Errorf("命令执行失败: %v", err)
+		return i18n.Errorf("命令执行失败: %v", err)
 	}
 	slog.Debug("命令执行成功")
 
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"AI-Shell/internal/cassette"
+	"AI-Shell/internal/config"
+)
+
+// TestExecuteReplaysCassette 回放 testdata/cassettes 中录制的响应，不访问网络，覆盖 exec 的完整流程：
+// 收集系统信息、渲染提示、发送请求、解析回复和显示候选命令。录制只按模型和用户请求匹配，
+// 与录制时的机器、目录和系统信息无关
+func TestExecuteReplaysCassette(t *testing.T) {
+	cassettes, err := filepath.Abs("testdata/cassettes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configDir := t.TempDir()
+	oldDir := config.Dir()
+	config.SetDir(configDir)
+	t.Cleanup(func() { config.SetDir(oldDir) })
+
+	// 地址中的主机不会被访问，回放只匹配路径
+	cfg := `{"url": "http://127.0.0.1:9/v1/chat/completions", "api_key": "sk-test", "model": "gpt-test",
+		"max_tokens": 1000, "temperature": 0.7, "retry": {"max_retries": 0}}`
+	if err := os.WriteFile(filepath.Join(configDir, "ais_config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(cassette.EnvVar, "replay:"+cassettes)
+	t.Chdir(t.TempDir())
+
+	// 选择 0 退出，不执行命令
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.WriteString("0\n")
+	input.Close()
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	t.Cleanup(func() { os.Stdin, os.Stdout = oldStdin, oldStdout })
+
+	rootCmd.SetArgs([]string{"exec", "列出当前目录的文件"})
+	err = rootCmd.ExecuteContext(context.Background())
+	os.Stdin, os.Stdout = oldStdin, oldStdout
+	if err != nil {
+		t.Fatalf("exec error = %v", err)
+	}
+
+	output, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"1: ls", "2: ls -la", "3: find . -maxdepth 1"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
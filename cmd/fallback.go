@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fallbackCmd = &cobra.Command{
+		Use:   "fallback",
+		Short: "备用模型管理命令",
+		Long: `管理备用模型。主模型因限流、服务端错误或网络故障不可用时，按顺序改用备用模型，
+回答请求的备用模型显示在回复下方，并记录在 ais usage 中。
+
+备用模型可以使用不同的服务和密钥。未指定地址时使用主配置的地址、密钥、请求头和附加字段；
+使用其他地址时不会发送主配置的密钥和请求头，服务需要认证时通过 --key 指定密钥。
+本地的 Ollama 模型适合作为最后一个备用模型，使 ais 在离线时仍然可用。`,
+	}
+
+	fallbackAddCmd = &cobra.Command{
+		Use:   "add [MODEL]",
+		Short: "添加备用模型",
+		Long: `将备用模型添加到列表末尾，例如:
+  ais fallback add gpt-4o --url https://api.example.com/v1/chat/completions --key '${EXAMPLE_API_KEY}'
+  ais fallback add llama3.2 --url http://localhost:11434/v1/chat/completions`,
+		Args: cobra.ExactArgs(1),
+		RunE: runFallbackAdd,
+	}
+
+	fallbackListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出备用模型",
+		Long:  `按尝试顺序列出备用模型。`,
+		Args:  cobra.NoArgs,
+		RunE:  runFallbackList,
+	}
+
+	fallbackRemoveCmd = &cobra.Command{
+		Use:   "rm [INDEX]",
+		Short: "删除备用模型",
+		Long:  `删除 ais fallback list 中指定序号的备用模型。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runFallbackRemove,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(fallbackCmd)
+	fallbackCmd.AddCommand(fallbackAddCmd, fallbackListCmd, fallbackRemoveCmd)
+	fallbackAddCmd.Flags().String("url", "", "备用模型的 API 地址，为空时使用主配置的地址")
+	fallbackAddCmd.Flags().String("key", "", "备用模型的 API 密钥，可以使用 ${NAME} 引用环境变量，未指定 --url 时默认使用主配置的密钥")
+}
+
+func runFallbackAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	url, _ := cmd.Flags().GetString("url")
+	key, _ := cmd.Flags().GetString("key")
+
+	if err := cfg.AddFallback(config.Fallback{URL: url, APIKey: key, Model: args[0]}); err != nil {
+		return i18n.Errorf("添加备用模型失败: %v", err)
+	}
+	fmt.Printf(i18n.T("已添加备用模型 %d: %s\n"), len(cfg.Fallbacks), args[0])
+	return nil
+}
+
+func runFallbackList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if len(cfg.Fallbacks) == 0 {
+		fmt.Println(i18n.T("还没有备用模型，使用 ais fallback add 添加"))
+		return nil
+	}
+
+	fmt.Printf(i18n.T("主模型: %s @ %s\n"), cfg.Model, cfg.URL)
+	for i, fallback := range cfg.Fallbacks {
+		url := fallback.URL
+		if url == "" {
+			url = i18n.T("(主配置的地址)")
+		}
+		fmt.Printf("%d: %s @ %s", i+1, fallback.Model, url)
+		if fallback.APIKey != "" {
+			fmt.Print(i18n.T("  (独立的密钥)"))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runFallbackRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	index, err := strconv.Atoi(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的序号: %s", args[0])
+	}
+
+	model := ""
+	if index >= 1 && index <= len(cfg.Fallbacks) {
+		model = cfg.Fallbacks[index-1].Model
+	}
+	if err := cfg.RemoveFallback(index); err != nil {
+		return i18n.Errorf("删除备用模型失败: %v", err)
+	}
+	fmt.Printf(i18n.T("已删除备用模型 %d: %s\n"), index, model)
+	return nil
+}
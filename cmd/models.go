@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/openai"
+
+	"github.com/spf13/cobra"
+)
+
+// modelLookupTimeout 补全和校验模型名称时获取模型列表的超时时间，避免阻塞 shell
+const modelLookupTimeout = 3 * time.Second
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "列出可用的模型",
+	Long: `列出当前 API 服务提供的模型，当前使用的模型以 * 标注。
+
+兼容 OpenAI 的服务通过 /v1/models 获取，Ollama 通过 /api/tags 获取。
+结果缓存在配置目录的 models_cache.json 中 24 小时，使用 --refresh 重新获取。
+使用 --pick 从列表中选择模型并写入配置。`,
+	Args: cobra.NoArgs,
+	RunE: runModels,
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+	modelsCmd.Flags().Bool("refresh", false, "忽略缓存，重新获取模型列表")
+	modelsCmd.Flags().Bool("pick", false, "从列表中选择模型并写入配置")
+	setModelCmd.ValidArgsFunction = completeModels
+}
+
+func runModels(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	pick, _ := cmd.Flags().GetBool("pick")
+
+	client, err := openai.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	models, err := client.Models(cmd.Context(), refresh)
+	if err != nil {
+		return explainRequestError(err, cfg)
+	}
+	if len(models) == 0 {
+		fmt.Println(i18n.T("服务没有返回任何模型"))
+		return nil
+	}
+
+	found := false
+	for i, model := range models {
+		marker := " "
+		if model.ID == cfg.Model {
+			marker, found = "*", true
+		}
+		line := fmt.Sprintf("%s %s", marker, model.ID)
+		if pick {
+			line = fmt.Sprintf("%3d: %s", i+1, line)
+		}
+		if model.ContextLength > 0 {
+			line += "  " + i18n.Sprintf("上下文 %d tokens", model.ContextLength)
+		}
+		fmt.Println(line)
+	}
+	if !found {
+		fmt.Printf(i18n.T("注意: 当前模型 %s 不在列表中\n"), cfg.Model)
+	}
+	if !pick {
+		return nil
+	}
+
+	fmt.Print(i18n.T("请选择模型 (0: 退出): "))
+	var choice string
+	fmt.Scanln(&choice)
+	num, err := strconv.Atoi(choice)
+	if err != nil || num < 0 || num > len(models) {
+		return i18n.Errorf("无效的选择")
+	}
+	if num == 0 {
+		return nil
+	}
+	model := models[num-1].ID
+	if err := cfg.SetModel(model); err != nil {
+		return i18n.Errorf("设置模型失败: %v", err)
+	}
+	fmt.Printf(i18n.T("已设置 MODEL = %s\n"), model)
+	return nil
+}
+
+// lookupModels 返回缓存的模型列表，没有缓存时在较短的超时时间内获取，失败时返回 nil
+func lookupModels(cfg *config.Config) []openai.Model {
+	if models, ok := openai.CachedModels(cfg); ok {
+		return models
+	}
+	client, err := openai.NewClient(cfg)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), modelLookupTimeout)
+	defer cancel()
+	models, err := client.Models(ctx, false)
+	if err != nil {
+		slog.Debug("获取模型列表失败", "error", err)
+		return nil
+	}
+	return models
+}
+
+// completeModels 为 config set model 补全模型名称
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, model := range lookupModels(cfg) {
+		ids = append(ids, model.ID)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// warnUnknownModel 模型不在服务提供的模型列表中时发出提示，获取不到模型列表时不做检查
+func warnUnknownModel(cfg *config.Config, model string) {
+	models := lookupModels(cfg)
+	if models == nil {
+		return
+	}
+	if !slices.ContainsFunc(models, func(m openai.Model) bool { return m.ID == model }) {
+		fmt.Printf(i18n.T("注意: 模型 %s 不在 %s 提供的模型列表中，请运行 ais models 查看可用的模型\n"), model, cfg.URL)
+	}
+}
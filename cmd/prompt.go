@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/prompt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptCmd = &cobra.Command{
+		Use:   "prompt",
+		Short: "系统提示模板管理命令",
+		Long: `管理发送给模型的系统提示模板。
+
+模板使用 Go text/template 语法，可用变量:
+  {{.SystemInfo}}     收集到的系统信息
+  {{.Shell}}          当前 shell 名称
+  {{.Locale}}         当前语言环境
+  {{.Language}}       模型回复 msg 时使用的语言
+  {{.MinCandidates}}  最少返回的候选命令数
+  {{.MaxCandidates}}  最多返回的候选命令数
+  {{.Diverse}}        是否要求多样化的候选命令
+  {{.Confidence}}     是否要求模型给出置信度
+  {{.Rules}}          用户配置和项目配置中的额外规则`,
+	}
+
+	promptShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "显示当前生效的提示模板",
+		Long:  `显示当前生效的提示模板，使用 --render 显示渲染后的系统提示。`,
+		Args:  cobra.NoArgs,
+		RunE:  runPromptShow,
+	}
+
+	promptEditCmd = &cobra.Command{
+		Use:   "edit",
+		Short: "编辑自定义提示模板",
+		Long:  `使用 $VISUAL 或 $EDITOR 编辑自定义提示模板，不存在时以当前内置模板为初始内容。`,
+		Args:  cobra.NoArgs,
+		RunE:  runPromptEdit,
+	}
+
+	promptResetCmd = &cobra.Command{
+		Use:   "reset",
+		Short: "恢复内置提示模板",
+		Long:  `删除自定义提示模板，恢复使用内置提示模板。`,
+		Args:  cobra.NoArgs,
+		RunE:  runPromptReset,
+	}
+
+	setPromptCmd = &cobra.Command{
+		Use:       "prompt [zh|en]",
+		Short:     "设置内置提示模板",
+		Long:      `设置没有自定义模板时使用的内置提示模板。`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: prompt.BuiltinNames(),
+		RunE:      runSetPrompt,
+	}
+
+	renderPrompt bool
+)
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.AddCommand(promptShowCmd, promptEditCmd, promptResetCmd)
+	setCmd.AddCommand(setPromptCmd)
+
+	promptShowCmd.Flags().BoolVarP(&renderPrompt, "render", "r", false, "显示渲染后的系统提示")
+}
+
+func runPromptShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	source, custom, err := prompt.Source(cfg)
+	if err != nil {
+		return err
+	}
+
+	if custom {
+		fmt.Printf(i18n.T("# 自定义模板: %s\n"), prompt.CustomPath())
+	} else {
+		fmt.Printf(i18n.T("# 内置模板: %s\n"), prompt.BuiltinName(cfg))
+	}
+
+	if !renderPrompt {
+		fmt.Println(source)
+		return nil
+	}
+
+	project, err := config.FindProjectConfig(".")
+	if err != nil {
+		return i18n.Errorf("加载项目配置失败: %v", err)
+	}
+	cfg = cfg.WithProject(project)
+	applyCandidateFlags(cmd, cfg)
+	rendered, err := prompt.Render(source, prompt.NewData(cfg, ""))
+	if err != nil {
+		return err
+	}
+	fmt.Println(rendered)
+	return nil
+}
+
+func runPromptEdit(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	path := prompt.CustomPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		source, err := prompt.Builtin(prompt.BuiltinName(cfg))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			return i18n.Errorf("创建自定义模板失败: %v", err)
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// 通过 shell 启动编辑器，以支持 EDITOR="code --wait" 这类带参数的写法
+	editCmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return i18n.Errorf("启动编辑器失败: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.Errorf("读取自定义模板失败: %v", err)
+	}
+	if _, err := prompt.Parse(string(data)); err != nil {
+		return i18n.Errorf("%v，请重新编辑或执行 ais prompt reset", err)
+	}
+
+	fmt.Printf(i18n.T("已保存自定义模板: %s\n"), path)
+	return nil
+}
+
+func runPromptReset(cmd *cobra.Command, args []string) error {
+	if err := prompt.Reset(); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("已恢复内置提示模板"))
+	return nil
+}
+
+func runSetPrompt(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if _, err := prompt.Builtin(args[0]); err != nil {
+		return err
+	}
+
+	if err := cfg.SetPrompt(args[0]); err != nil {
+		return i18n.Errorf("设置提示模板失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 PROMPT = %s\n"), args[0])
+	return nil
+}
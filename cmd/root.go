@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"AI-Shell/internal/config"
-	"fmt"
 	"log/slog"
 
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -32,9 +34,9 @@ var rootCmd = &cobra.Command{
 		// 加载配置
 		cfg, err := config.LoadConfig()
 		if err != nil {
-			return fmt.Errorf("加载配置失败: %w", err)
+			return i18n.Errorf("加载配置失败: %w", err)
 		}
-		
+
 		// 设置日志级别
 		if cfg.Debug {
 			slog.SetLogLoggerLevel(slog.LevelDebug)
@@ -44,14 +46,38 @@ var rootCmd = &cobra.Command{
 			slog.SetLogLoggerLevel(slog.LevelDebug)
 		}
 
-		return nil			
+		return nil
 	},
 }
 
 func Execute() error {
+	// 帮助信息在 PersistentPreRunE 之前就可能输出，所以在这里提前确定界面语言
+	if cfg, err := config.LoadConfig(); err == nil {
+		if err := i18n.SetLanguage(cfg.Language); err != nil {
+			slog.Warn(err.Error())
+		}
+	}
+	localizeCommand(rootCmd)
+
 	return rootCmd.Execute()
 }
 
+// localizeCommand 递归翻译命令及其标志的说明文本
+func localizeCommand(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+
+	localizeFlag := func(flag *pflag.Flag) {
+		flag.Usage = i18n.T(flag.Usage)
+	}
+	cmd.LocalFlags().VisitAll(localizeFlag)
+	cmd.PersistentFlags().VisitAll(localizeFlag)
+
+	for _, child := range cmd.Commands() {
+		localizeCommand(child)
+	}
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&showData, "show-data", "s", false, "显示发送到API的数据")
 	rootCmd.PersistentFlags().BoolVarP(&debugMode, "debug", "d", false, "激活 debug 日志模式")
@@ -3,9 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
 
 	"github.com/spf13/cobra"
 )
@@ -78,6 +80,167 @@ var (
 		Args:  cobra.ExactArgs(1),
 		RunE:  runSetDebug,
 	}
+
+	setMinCandidatesCmd = &cobra.Command{
+		Use:   "min-candidates [NUMBER]",
+		Short: "设置最少候选命令数",
+		Long:  `设置模型最少返回的候选命令数。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetMinCandidates,
+	}
+
+	setMaxCandidatesCmd = &cobra.Command{
+		Use:   "max-candidates [NUMBER]",
+		Short: "设置最多候选命令数",
+		Long:  `设置模型最多返回的候选命令数，设置为1时只返回一条命令。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetMaxCandidates,
+	}
+
+	setDiverseCmd = &cobra.Command{
+		Use:   "diverse [true|false]",
+		Short: "设置多样化候选命令",
+		Long:  `启用后要求模型给出使用不同工具或写法的候选命令，例如可移植写法和 GNU 专用写法。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetDiverse,
+	}
+
+	setSortConfidenceCmd = &cobra.Command{
+		Use:   "sort-confidence [true|false]",
+		Short: "设置按置信度排序",
+		Long:  `启用后要求模型给出每条候选命令的置信度，并按置信度从高到低排序。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetSortConfidence,
+	}
+
+	setRedactCmd = &cobra.Command{
+		Use:   "redact [true|false]",
+		Short: "设置脱敏",
+		Long:  `启用后在发送给 API 之前隐藏密钥和令牌、用户主目录、用户名以及 .env、*.pem 等敏感文件名。默认启用。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetRedact,
+	}
+
+	setListingMaxCmd = &cobra.Command{
+		Use:   "listing-max [NUMBER]",
+		Short: "设置目录列表最大条目数",
+		Long:  `设置发送给模型的目录列表最多包含的条目数，超过后只发送按类型和扩展名的统计以及部分条目，0 表示使用默认值。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetListingMax,
+	}
+
+	setListingTreeCmd = &cobra.Command{
+		Use:   "listing-tree [true|false]",
+		Short: "设置目录树",
+		Long:  `启用后发送深度为 2 的目录树，.gitignore 中忽略的文件不会列出。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetListingTree,
+	}
+
+	setHistoryEntriesCmd = &cobra.Command{
+		Use:   "history-entries [NUMBER]",
+		Short: "设置历史记录条数",
+		Long:  `设置 history 收集器发送给模型的最近历史记录条数，0 表示使用默认值。history 收集器默认禁用，使用 ais collector enable history 启用。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetHistoryEntries,
+	}
+
+	setCollectorTimeoutCmd = &cobra.Command{
+		Use:   "collector-timeout [MILLISECONDS]",
+		Short: "设置收集器默认超时时间",
+		Long:  `设置上下文收集器默认的超时时间（毫秒），超时的收集器会被跳过，0 表示使用默认值。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetCollectorTimeout,
+	}
+
+	setRetriesCmd = &cobra.Command{
+		Use:   "retries [NUMBER]",
+		Short: "设置重试次数",
+		Long:  `设置请求遇到限流、服务端错误或网络中断时的最多重试次数，0 表示不重试。重试间隔按指数退避增长，并遵循服务端返回的 Retry-After。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetRetries,
+	}
+
+	setPriceCmd = &cobra.Command{
+		Use:   "price [MODEL] [INPUT] [OUTPUT]",
+		Short: "设置模型价格",
+		Long:  `设置模型每百万 tokens 的输入和输出价格，用于统计费用。模型名称也可以是前缀，例如 gpt-4o 的价格同样适用于 gpt-4o-2024-08-06。`,
+		Args:  cobra.ExactArgs(3),
+		RunE:  runSetPrice,
+	}
+
+	setBudgetSoftCmd = &cobra.Command{
+		Use:   "budget-soft [AMOUNT]",
+		Short: "设置月度软性预算",
+		Long:  `设置每月费用的软性预算，本月费用超过后每次请求都会发出警告，0 表示不限制。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetBudgetSoft,
+	}
+
+	setBudgetHardCmd = &cobra.Command{
+		Use:   "budget-hard [AMOUNT]",
+		Short: "设置月度硬性预算",
+		Long:  `设置每月费用的硬性预算，本月费用达到后拒绝发送请求，0 表示不限制。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetBudgetHard,
+	}
+
+	setProxyCmd = &cobra.Command{
+		Use:   "proxy [PROXY_URL]",
+		Short: "设置代理",
+		Long:  `设置访问 API 使用的代理，支持 http、https 和 socks5，例如 http://proxy.example.com:3128。设置为空字符串时使用 HTTPS_PROXY 等环境变量。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetProxy,
+	}
+
+	setCAFileCmd = &cobra.Command{
+		Use:   "ca-file [PATH]",
+		Short: "设置 CA 证书",
+		Long:  `设置在系统证书之外额外信任的 CA 证书文件（PEM 格式），用于访问使用企业 CA 签发证书的服务。设置为空字符串时只信任系统证书。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetCAFile,
+	}
+
+	setClientCertCmd = &cobra.Command{
+		Use:   "client-cert [CERT_FILE] [KEY_FILE]",
+		Short: "设置客户端证书",
+		Long:  `设置 mTLS 使用的客户端证书和私钥文件（PEM 格式）。都设置为空字符串时不使用客户端证书。`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSetClientCert,
+	}
+
+	setInsecureSkipVerifyCmd = &cobra.Command{
+		Use:   "insecure-skip-verify [true|false]",
+		Short: "设置是否跳过证书校验",
+		Long:  `设置是否跳过服务端证书校验。跳过校验后连接可能被窃听或篡改，请只在调试时使用。`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetInsecureSkipVerify,
+	}
+
+	setHeaderCmd = &cobra.Command{
+		Use:   "header [NAME] [VALUE]",
+		Short: "设置请求头",
+		Long:  `设置请求 API 时附加的 HTTP 头，例如 OpenAI-Organization、HTTP-Referer，可以覆盖默认的 Authorization。值中可以使用 ${NAME} 引用环境变量，值为空字符串时删除该请求头。`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSetHeader,
+	}
+
+	setExtraBodyCmd = &cobra.Command{
+		Use:   "extra-body [KEY] [VALUE]",
+		Short: "设置请求体字段",
+		Long:  `设置合并到请求体中的字段，例如 top_p、seed、reasoning_effort。值按 JSON 解析，不是有效的 JSON 时作为字符串，字符串中可以使用 ${NAME} 引用环境变量。值为空字符串时删除该字段。`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSetExtraBody,
+	}
+
+	setLanguageCmd = &cobra.Command{
+		Use:       "language [auto|zh|en]",
+		Short:     "设置界面语言",
+		Long:      `设置界面和模型回复使用的语言，auto 表示根据 LANG、LC_MESSAGES 环境变量自动检测。`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: append([]string{"auto"}, i18n.Supported()...),
+		RunE:      runSetLanguage,
+	}
 )
 
 func init() {
@@ -94,122 +257,551 @@ func init() {
 	setCmd.AddCommand(setMaxTokensCmd)
 	setCmd.AddCommand(setTemperatureCmd)
 	setCmd.AddCommand(setDebugCmd)
+	setCmd.AddCommand(setLanguageCmd)
+	setCmd.AddCommand(setMinCandidatesCmd)
+	setCmd.AddCommand(setMaxCandidatesCmd)
+	setCmd.AddCommand(setDiverseCmd)
+	setCmd.AddCommand(setSortConfidenceCmd)
+	setCmd.AddCommand(setRedactCmd)
+	setCmd.AddCommand(setListingMaxCmd)
+	setCmd.AddCommand(setListingTreeCmd)
+	setCmd.AddCommand(setHistoryEntriesCmd)
+	setCmd.AddCommand(setCollectorTimeoutCmd)
+	setCmd.AddCommand(setRetriesCmd)
+	setCmd.AddCommand(setPriceCmd)
+	setCmd.AddCommand(setBudgetSoftCmd)
+	setCmd.AddCommand(setBudgetHardCmd)
+	setCmd.AddCommand(setProxyCmd)
+	setCmd.AddCommand(setCAFileCmd)
+	setCmd.AddCommand(setClientCertCmd)
+	setCmd.AddCommand(setInsecureSkipVerifyCmd)
+	setCmd.AddCommand(setHeaderCmd)
+	setCmd.AddCommand(setExtraBodyCmd)
 }
 
 func runView(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
-	fmt.Printf("当前配置:\n")
+	fmt.Print(i18n.T("当前配置:\n"))
 	jsonData, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return fmt.Errorf("序列化配置失败: %v", err)
+		return i18n.Errorf("序列化配置失败: %v", err)
 	}
 	fmt.Println(string(jsonData))
 
+	project, err := config.FindProjectConfig(".")
+	if err != nil {
+		return i18n.Errorf("加载项目配置失败: %v", err)
+	}
+	if project != nil {
+		fmt.Printf(i18n.T("\n项目配置 (%s):\n"), project.Path)
+		projectData, err := json.MarshalIndent(project, "", "  ")
+		if err != nil {
+			return i18n.Errorf("序列化配置失败: %v", err)
+		}
+		fmt.Println(string(projectData))
+	}
+
 	return nil
 }
 
 func runSetURL(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
 	if err := cfg.SetURL(args[0]); err != nil {
-		return fmt.Errorf("设置URL失败: %v", err)
+		return i18n.Errorf("设置URL失败: %v", err)
 	}
 
-	fmt.Printf("已设置 URL = %s\n", args[0])
+	fmt.Printf(i18n.T("已设置 URL = %s\n"), args[0])
 	return nil
 }
 
 func runSetKey(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
 	if err := cfg.SetAPIKey(args[0]); err != nil {
-		return fmt.Errorf("设置API密钥失败: %v", err)
+		return i18n.Errorf("设置API密钥失败: %v", err)
 	}
 
-	fmt.Printf("已设置 API_KEY = %s\n", args[0])
+	fmt.Printf(i18n.T("已设置 API_KEY = %s\n"), args[0])
 	return nil
 }
 
 func runSetModel(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
 	if err := cfg.SetModel(args[0]); err != nil {
-		return fmt.Errorf("设置模型失败: %v", err)
+		return i18n.Errorf("设置模型失败: %v", err)
 	}
 
-	fmt.Printf("已设置 MODEL = %s\n", args[0])
+	fmt.Printf(i18n.T("已设置 MODEL = %s\n"), args[0])
+	warnUnknownModel(cfg, args[0])
 	return nil
 }
 
 func runSetMaxTokens(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
 	maxTokens, err := strconv.Atoi(args[0])
 	if err != nil {
-		return fmt.Errorf("无效的最大令牌数值: %v", err)
+		return i18n.Errorf("无效的最大令牌数值: %v", err)
 	}
 
 	if err := cfg.SetMaxTokens(maxTokens); err != nil {
-		return fmt.Errorf("设置最大令牌数失败: %v", err)
+		return i18n.Errorf("设置最大令牌数失败: %v", err)
 	}
 
-	fmt.Printf("已设置 MAX_TOKENS = %d\n", maxTokens)
+	fmt.Printf(i18n.T("已设置 MAX_TOKENS = %d\n"), maxTokens)
 	return nil
 }
 
 func runSetTemperature(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 
 	temp, err := strconv.ParseFloat(args[0], 64)
 	if err != nil {
-		return fmt.Errorf("无效的温度参数值: %v", err)
+		return i18n.Errorf("无效的温度参数值: %v", err)
 	}
 
 	if temp < 0 || temp > 1 {
-		return fmt.Errorf("温度参数必须在0到1之间")
+		return i18n.Errorf("温度参数必须在0到1之间")
 	}
 
 	if err := cfg.SetTemperature(temp); err != nil {
-		return fmt.Errorf("设置温度参数失败: %v", err)
+		return i18n.Errorf("设置温度参数失败: %v", err)
 	}
 
-	fmt.Printf("已设置 TEMPERATURE = %.1f\n", temp)
+	fmt.Printf(i18n.T("已设置 TEMPERATURE = %.1f\n"), temp)
 	return nil
 }
 
 func runSetDebug(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("加载配置失败: %v", err)
+		return i18n.Errorf("加载配置失败: %v", err)
 	}
 	debugMode, err := strconv.ParseBool(args[0])
 	if err != nil {
-		return fmt.Errorf("无效的Debug模式值: %v", err)
+		return i18n.Errorf("无效的Debug模式值: %v", err)
 	}
 
 	if err := cfg.SetDebug(debugMode); err != nil {
-		return fmt.Errorf("设置Debug模式失败: %v", err)
+		return i18n.Errorf("设置Debug模式失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 Debug 模式 = %v\n"), debugMode)
+	return nil
+}
+
+func runSetLanguage(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if err := i18n.SetLanguage(args[0]); err != nil {
+		return err
+	}
+
+	language := args[0]
+	if language == "auto" {
+		language = ""
+	} else {
+		language = i18n.Language()
+	}
+
+	if err := cfg.SetLanguage(language); err != nil {
+		return i18n.Errorf("设置语言失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 LANGUAGE = %s\n"), args[0])
+	return nil
+}
+
+func runSetMinCandidates(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 1 {
+		return i18n.Errorf("候选命令数必须是正整数: %s", args[0])
+	}
+	if _, max := cfg.CandidateRange(); count > max {
+		return i18n.Errorf("最少候选命令数不能大于最多候选命令数 %d", max)
+	}
+
+	if err := cfg.SetMinCandidates(count); err != nil {
+		return i18n.Errorf("设置候选命令数失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 MIN_CANDIDATES = %d\n"), count)
+	return nil
+}
+
+func runSetMaxCandidates(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 1 {
+		return i18n.Errorf("候选命令数必须是正整数: %s", args[0])
+	}
+	if cfg.MinCandidates > count {
+		return i18n.Errorf("最多候选命令数不能小于最少候选命令数 %d", cfg.MinCandidates)
+	}
+
+	if err := cfg.SetMaxCandidates(count); err != nil {
+		return i18n.Errorf("设置候选命令数失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 MAX_CANDIDATES = %d\n"), count)
+	return nil
+}
+
+func runSetDiverse(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	diverse, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的布尔值: %v", err)
+	}
+
+	if err := cfg.SetDiverse(diverse); err != nil {
+		return i18n.Errorf("设置多样化候选命令失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 DIVERSE = %v\n"), diverse)
+	return nil
+}
+
+func runSetSortConfidence(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	sortByConfidence, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的布尔值: %v", err)
+	}
+
+	if err := cfg.SetSortByConfidence(sortByConfidence); err != nil {
+		return i18n.Errorf("设置按置信度排序失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 SORT_CONFIDENCE = %v\n"), sortByConfidence)
+	return nil
+}
+
+func runSetRedact(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	enabled, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的布尔值: %v", err)
+	}
+
+	if err := cfg.SetRedact(enabled); err != nil {
+		return i18n.Errorf("设置脱敏失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 REDACT = %v\n"), enabled)
+	return nil
+}
+
+func runSetListingMax(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 0 {
+		return i18n.Errorf("条目数必须是非负整数: %s", args[0])
+	}
+
+	if err := cfg.SetListingMaxEntries(count); err != nil {
+		return i18n.Errorf("设置目录列表最大条目数失败: %v", err)
 	}
 
-	fmt.Printf("已设置 Debug 模式 = %v\n", debugMode)
+	fmt.Printf(i18n.T("已设置 LISTING_MAX = %d\n"), count)
+	return nil
+}
+
+func runSetListingTree(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	tree, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的布尔值: %v", err)
+	}
+
+	if err := cfg.SetListingTree(tree); err != nil {
+		return i18n.Errorf("设置目录树失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 LISTING_TREE = %v\n"), tree)
+	return nil
+}
+
+func runSetHistoryEntries(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 0 {
+		return i18n.Errorf("条目数必须是非负整数: %s", args[0])
+	}
+
+	if err := cfg.SetHistoryEntries(count); err != nil {
+		return i18n.Errorf("设置历史记录条数失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 HISTORY_ENTRIES = %d\n"), count)
+	return nil
+}
+
+func runSetCollectorTimeout(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	timeout, err := strconv.Atoi(args[0])
+	if err != nil || timeout < 0 {
+		return i18n.Errorf("超时时间必须是非负整数: %s", args[0])
+	}
+
+	if err := cfg.SetDefaultCollectorTimeout(timeout); err != nil {
+		return i18n.Errorf("设置收集器默认超时时间失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 COLLECTOR_TIMEOUT = %d ms\n"), timeout)
+	return nil
+}
+
+func runSetRetries(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	retries, err := strconv.Atoi(args[0])
+	if err != nil || retries < 0 {
+		return i18n.Errorf("重试次数必须是非负整数: %s", args[0])
+	}
+
+	if err := cfg.SetMaxRetries(retries); err != nil {
+		return i18n.Errorf("设置重试次数失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 RETRIES = %d\n"), retries)
+	return nil
+}
+
+func runSetPrice(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	input, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || input < 0 {
+		return i18n.Errorf("价格必须是非负数: %s", args[1])
+	}
+	output, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || output < 0 {
+		return i18n.Errorf("价格必须是非负数: %s", args[2])
+	}
+
+	if err := cfg.SetModelPrice(args[0], input, output); err != nil {
+		return i18n.Errorf("设置模型价格失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 %s 的价格: 输入 %g / 输出 %g %s 每百万 tokens\n"), args[0], input, output, cfg.Usage.CurrencyOrDefault())
+	return nil
+}
+
+func runSetBudgetSoft(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	budget, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || budget < 0 {
+		return i18n.Errorf("预算必须是非负数: %s", args[0])
+	}
+
+	if err := cfg.SetSoftBudget(budget); err != nil {
+		return i18n.Errorf("设置预算失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 BUDGET_SOFT = %g %s\n"), budget, cfg.Usage.CurrencyOrDefault())
+	return nil
+}
+
+func runSetBudgetHard(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	budget, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || budget < 0 {
+		return i18n.Errorf("预算必须是非负数: %s", args[0])
+	}
+
+	if err := cfg.SetHardBudget(budget); err != nil {
+		return i18n.Errorf("设置预算失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 BUDGET_HARD = %g %s\n"), budget, cfg.Usage.CurrencyOrDefault())
+	return nil
+}
+
+func runSetProxy(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if err := cfg.SetProxy(args[0]); err != nil {
+		return i18n.Errorf("设置代理失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 PROXY = %s\n"), args[0])
+	return nil
+}
+
+func runSetCAFile(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if err := cfg.SetCAFile(args[0]); err != nil {
+		return i18n.Errorf("设置 CA 证书失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 CA_FILE = %s\n"), args[0])
+	return nil
+}
+
+func runSetClientCert(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	if (args[0] == "") != (args[1] == "") {
+		return i18n.Errorf("客户端证书和私钥需要同时设置")
+	}
+
+	if err := cfg.SetClientCert(args[0], args[1]); err != nil {
+		return i18n.Errorf("设置客户端证书失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 CLIENT_CERT = %s, CLIENT_KEY = %s\n"), args[0], args[1])
+	return nil
+}
+
+func runSetInsecureSkipVerify(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	skip, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return i18n.Errorf("无效的布尔值: %v", err)
+	}
+
+	if err := cfg.SetInsecureSkipVerify(skip); err != nil {
+		return i18n.Errorf("设置证书校验失败: %v", err)
+	}
+
+	fmt.Printf(i18n.T("已设置 INSECURE_SKIP_VERIFY = %v\n"), skip)
+	if skip {
+		fmt.Println(i18n.T("已跳过服务端证书校验，连接可能被窃听或篡改，请只在调试时使用"))
+	}
+	return nil
+}
+
+func runSetHeader(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	name := http.CanonicalHeaderKey(args[0])
+	if err := cfg.SetHeader(name, args[1]); err != nil {
+		return i18n.Errorf("设置请求头失败: %v", err)
+	}
+
+	if args[1] == "" {
+		fmt.Printf(i18n.T("已删除请求头 %s\n"), name)
+		return nil
+	}
+	fmt.Printf(i18n.T("已设置请求头 %s = %s\n"), name, args[1])
+	return nil
+}
+
+func runSetExtraBody(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	var value any
+	if args[1] != "" {
+		if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+			value = args[1]
+		}
+	}
+
+	if err := cfg.SetExtraBody(args[0], value); err != nil {
+		return i18n.Errorf("设置请求体字段失败: %v", err)
+	}
+
+	if value == nil {
+		fmt.Printf(i18n.T("已删除请求体字段 %s\n"), args[0])
+		return nil
+	}
+	data, _ := json.Marshal(value)
+	fmt.Printf(i18n.T("已设置请求体字段 %s = %s\n"), args[0], data)
 	return nil
 }
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/openai"
+	"AI-Shell/internal/usage"
+
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "查看令牌用量和费用",
+	Long: `按日期和模型显示令牌用量和费用，默认显示本月。
+
+费用按记录时的价格计算，价格通过 ais config set price 设置，没有设置价格的模型费用按 0 计算。
+可以通过 ais config set budget-soft 和 ais config set budget-hard 设置月度预算。`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+	usageCmd.Flags().String("month", "", "要查看的月份，格式为 2006-01，默认为本月")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return i18n.Errorf("加载配置失败: %v", err)
+	}
+
+	month := time.Now()
+	if value, _ := cmd.Flags().GetString("month"); value != "" {
+		month, err = time.ParseInLocation("2006-01", value, time.Local)
+		if err != nil {
+			return i18n.Errorf("无效的月份: %s，格式应为 2006-01", value)
+		}
+	}
+
+	store, err := usage.Load()
+	if err != nil {
+		return err
+	}
+	records := store.Month(month)
+	currency := cfg.Usage.CurrencyOrDefault()
+	if len(records) == 0 {
+		fmt.Printf(i18n.T("%s 没有用量记录\n"), month.Format("2006-01"))
+		return nil
+	}
+
+	rows := [][]string{{i18n.T("日期"), i18n.T("模型"), i18n.T("服务"), i18n.T("请求数"),
+		i18n.T("输入 tokens"), i18n.T("输出 tokens"), i18n.T("费用")}}
+	var total usage.Record
+	byModel := make(map[string]*usage.Record)
+	unpriced := make(map[string]bool)
+	for _, record := range records {
+		rows = append(rows, usageRow(record.Date, record.Model, record.Endpoint, record, currency))
+
+		summary, ok := byModel[record.Model]
+		if !ok {
+			summary = &usage.Record{Model: record.Model}
+			byModel[record.Model] = summary
+		}
+		for _, sum := range []*usage.Record{summary, &total} {
+			sum.Requests += record.Requests
+			sum.PromptTokens += record.PromptTokens
+			sum.CompletionTokens += record.CompletionTokens
+			sum.Cost += record.Cost
+		}
+		if record.Unpriced > 0 {
+			unpriced[record.Model] = true
+		}
+	}
+
+	rows = append(rows, nil)
+	models := make([]string, 0, len(byModel))
+	for model := range byModel {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	for _, model := range models {
+		rows = append(rows, usageRow(month.Format("2006-01"), model, "", *byModel[model], currency))
+	}
+	rows = append(rows, usageRow(month.Format("2006-01"), i18n.T("合计"), "", total, currency))
+	printTable(rows)
+
+	for _, model := range models {
+		if unpriced[model] {
+			fmt.Printf(i18n.T("注意: %s 有请求在记录时没有设置价格，费用按 0 计算\n"), model)
+		}
+	}
+	if cfg.Usage.SoftBudget > 0 {
+		fmt.Printf(i18n.T("软性预算: %s (已使用 %.0f%%)\n"), formatCost(cfg.Usage.SoftBudget, currency), total.Cost/cfg.Usage.SoftBudget*100)
+	}
+	if cfg.Usage.HardBudget > 0 {
+		fmt.Printf(i18n.T("硬性预算: %s (已使用 %.0f%%)\n"), formatCost(cfg.Usage.HardBudget, currency), total.Cost/cfg.Usage.HardBudget*100)
+	}
+	return nil
+}
+
+func usageRow(date, model, endpoint string, record usage.Record, currency string) []string {
+	return []string{date, model, endpoint, strconv.Itoa(record.Requests), strconv.Itoa(record.PromptTokens),
+		strconv.Itoa(record.CompletionTokens), formatCost(record.Cost, currency)}
+}
+
+// printTable 按显示宽度对齐输出表格，nil 行输出为空行。
+// text/tabwriter 按字符数对齐，表头为中文时无法对齐
+func printTable(rows [][]string) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		fmt.Println(strings.TrimRight(line.String(), " "))
+	}
+}
+
+// displayWidth 返回字符串在终端中的显示宽度，中日韩文字和全角字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hangul, r), unicode.Is(unicode.Hiragana, r),
+			unicode.Is(unicode.Katakana, r), r >= 0xFF00 && r <= 0xFF60, r >= 0x3000 && r <= 0x303F:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func formatCost(cost float64, currency string) string {
+	return fmt.Sprintf("%.4f %s", cost, currency)
+}
+
+// checkBudget 本月费用达到硬性预算时拒绝发送请求
+func checkBudget(cfg *config.Config) error {
+	if cfg.Usage.HardBudget <= 0 {
+		return nil
+	}
+	store, err := usage.Load()
+	if err != nil {
+		// 用量记录损坏时不应该影响正常使用
+		slog.Warn(err.Error())
+		return nil
+	}
+	currency := cfg.Usage.CurrencyOrDefault()
+	if cost := store.MonthCost(time.Now()); cost >= cfg.Usage.HardBudget {
+		return i18n.Errorf("本月费用 %s 已达到硬性预算 %s，请运行 ais config set budget-hard 调整预算",
+			formatCost(cost, currency), formatCost(cfg.Usage.HardBudget, currency))
+	}
+	return nil
+}
+
+// trackUsage 记录请求的用量，本月费用超过预算时发出警告
+func trackUsage(cfg *config.Config, result *openai.Result) {
+	monthCost, err := usage.Track(cfg, result.URL, result.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	if err != nil {
+		slog.Warn(i18n.Sprintf("记录用量失败: %v", err))
+		return
+	}
+	slog.Debug("用量已记录", "model", result.Model, "url", result.URL, "monthCost", monthCost)
+
+	currency := cfg.Usage.CurrencyOrDefault()
+	switch {
+	case cfg.Usage.HardBudget > 0 && monthCost >= cfg.Usage.HardBudget:
+		slog.Warn(i18n.Sprintf("本月费用 %s 已达到硬性预算 %s，之后的请求将被拒绝",
+			formatCost(monthCost, currency), formatCost(cfg.Usage.HardBudget, currency)))
+	case cfg.Usage.SoftBudget > 0 && monthCost >= cfg.Usage.SoftBudget:
+		slog.Warn(i18n.Sprintf("本月费用 %s 已超过软性预算 %s",
+			formatCost(monthCost, currency), formatCost(cfg.Usage.SoftBudget, currency)))
+	}
+}
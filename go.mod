@@ -2,9 +2,9 @@ module AI-Shell
 
 go 1.24.2
 
-require github.com/spf13/cobra v1.9.1
-
 require (
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
+	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
 )
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
@@ -0,0 +1,267 @@
+// Package cassette 录制和回放 HTTP 请求，用于在没有网络的环境中进行确定性的测试和演示。
+//
+// 录制模式下请求照常发送，请求和响应去掉密钥后以 JSON 文件保存在目录中；
+// 回放模式下不发送请求，按请求的哈希值查找录制的响应。
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/redact"
+)
+
+// EnvVar 为切换录制和回放模式的环境变量，取值为 record:目录 或 replay:目录
+const EnvVar = "AIS_CASSETTE"
+
+// sensitiveHeaders 为录制时丢弃的请求头和响应头
+var sensitiveHeaders = []string{"Authorization", "Api-Key", "X-Api-Key", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// Cassette 为一次录制的请求和响应
+type Cassette struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 为录制的请求
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Response 为录制的响应
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// FromEnv 根据 AIS_CASSETTE 环境变量包装 next，未设置时原样返回 next
+func FromEnv(next http.RoundTripper) (http.RoundTripper, error) {
+	value := os.Getenv(EnvVar)
+	if value == "" {
+		return next, nil
+	}
+	mode, dir, ok := strings.Cut(value, ":")
+	if !ok || dir == "" {
+		return nil, i18n.Errorf("无效的 %s: %s，格式应为 record:目录 或 replay:目录", EnvVar, value)
+	}
+
+	switch mode {
+	case "record":
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, i18n.Errorf("创建录制目录失败: %v", err)
+		}
+		slog.Debug("录制 HTTP 请求", "dir", dir)
+		return &Recorder{Dir: dir, Next: next}, nil
+	case "replay":
+		slog.Debug("回放录制的 HTTP 请求", "dir", dir)
+		return &Player{Dir: dir}, nil
+	}
+	return nil, i18n.Errorf("无效的 %s: %s，格式应为 record:目录 或 replay:目录", EnvVar, value)
+}
+
+// Recorder 发送请求并将请求和响应保存到 Dir
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	cassette := Cassette{
+		Request: Request{
+			Method: req.Method,
+			URL:    redact.Secrets(stripURL(req)),
+			Header: stripRequestHeaders(req.Header),
+			Body:   redact.Secrets(string(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     stripHeaders(resp.Header),
+			Body:       redact.Secrets(string(respBody)),
+		},
+	}
+	path := filepath.Join(r.Dir, Hash(req.Method, req.URL.Path, body)+".json")
+	if err := save(path, &cassette); err != nil {
+		// 录制失败不应该影响请求本身
+		slog.Warn(i18n.Sprintf("保存录制失败: %v", err))
+	} else {
+		slog.Debug("已录制请求", "path", path)
+	}
+	return resp, nil
+}
+
+// NoMatchError 回放模式下没有与请求匹配的录制
+type NoMatchError struct {
+	Path string // 按请求哈希值查找的录制文件
+}
+
+func (e *NoMatchError) Error() string {
+	return i18n.Sprintf("没有与请求匹配的录制: %s", e.Path)
+}
+
+// Player 按请求的哈希值从 Dir 中读取录制的响应，不发送请求
+type Player struct {
+	Dir string
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := Hash(req.Method, req.URL.Path, body)
+	path := filepath.Join(p.Dir, hash+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &NoMatchError{Path: path}
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, i18n.Errorf("解析录制失败: %s: %v", path, err)
+	}
+	slog.Debug("回放录制的响应", "path", path)
+
+	header := cassette.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        http.StatusText(cassette.Response.StatusCode),
+		StatusCode:    cassette.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(cassette.Response.Body)),
+		ContentLength: int64(len(cassette.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Hash 返回用于匹配录制的请求哈希值。只使用请求方法、路径和请求体中参与匹配的部分，
+// 主机名和密钥不同的请求仍然可以匹配同一份录制
+func Hash(method, path string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(method + " " + path + "\n"))
+	sum.Write(matchKey(body))
+	return hex.EncodeToString(sum.Sum(nil)[:12])
+}
+
+// matchKey 返回请求体中参与匹配的部分。补全请求只使用模型和用户请求，即用户消息的最后一行：
+// 系统提示和用户消息中的系统信息包含目录列表、git 状态、负载等随机器和时间变化的内容，
+// 参与匹配时录制无法在其他机器上回放。其他请求使用完整的请求体
+func matchKey(body []byte) []byte {
+	var req struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if json.Unmarshal(body, &req) != nil || len(req.Messages) == 0 {
+		return body
+	}
+	var query string
+	for _, message := range req.Messages {
+		if message.Role == "user" {
+			query = message.Content[strings.LastIndex(message.Content, "\n")+1:]
+		}
+	}
+	return []byte(req.Model + "\n" + query)
+}
+
+// readRequestBody 读取请求体，并重新设置请求体以便继续发送
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// sensitiveHeaderWords 出现在请求头名称中时，认为该请求头包含认证信息，如配置中附加的 X-Tenant-Token
+var sensitiveHeaderWords = []string{"key", "token", "secret", "auth", "password"}
+
+// stripHeaders 返回去掉 sensitiveHeaders 后的请求头或响应头。响应头只按名称列表去掉：
+// x-ratelimit-reset-tokens 等响应头的名称中含有 token，回放限流时需要保留
+func stripHeaders(header http.Header) http.Header {
+	stripped := header.Clone()
+	for _, name := range sensitiveHeaders {
+		stripped.Del(name)
+	}
+	return stripped
+}
+
+// stripRequestHeaders 返回去掉认证信息后的请求头，名称中含有 sensitiveHeaderWords 的请求头也会去掉
+func stripRequestHeaders(header http.Header) http.Header {
+	stripped := stripHeaders(header)
+	for name := range stripped {
+		lower := strings.ToLower(name)
+		for _, word := range sensitiveHeaderWords {
+			if strings.Contains(lower, word) {
+				stripped.Del(name)
+				break
+			}
+		}
+	}
+	return stripped
+}
+
+// sensitiveQuery 为录制时丢弃的查询参数，部分服务通过查询参数传递密钥
+var sensitiveQuery = []string{"key", "api_key", "api-key", "access_token"}
+
+// stripURL 返回去掉用户名、密码和密钥参数后的请求地址
+func stripURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	query := u.Query()
+	for _, name := range sensitiveQuery {
+		query.Del(name)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func save(path string, cassette *Cassette) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,64 @@
+package cassette
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordKeepsRateLimitHeadersAndStripsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Reset-Tokens", "6m0s")
+		w.Header().Set("X-Ratelimit-Remaining-Tokens", "0")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"Rate limit reached"}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	send := func(transport http.RoundTripper) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/chat/completions",
+			strings.NewReader(`{"model":"gpt-test","messages":[{"role":"user","content":"ls"}]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer sk-live")
+		req.Header.Set("X-Tenant-Token", "tenant-secret")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	send(&Recorder{Dir: dir, Next: http.DefaultTransport})
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("recorded %d files, want 1", len(files))
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"sk-live", "tenant-secret", "session=abc"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("recording contains %q", secret)
+		}
+	}
+
+	resp := send(&Player{Dir: dir})
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("replayed status = %d, want 429", resp.StatusCode)
+	}
+	for _, name := range []string{"X-Ratelimit-Reset-Tokens", "X-Ratelimit-Remaining-Tokens"} {
+		if resp.Header.Get(name) == "" {
+			t.Errorf("replayed response is missing %s", name)
+		}
+	}
+}
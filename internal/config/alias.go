@@ -0,0 +1,88 @@
+package config
+
+import (
+	"log/slog"
+	"slices"
+
+	"AI-Shell/internal/i18n"
+)
+
+// Alias 保存的提示，可以通过 ais @名称 调用
+type Alias struct {
+	Prompt string `json:"prompt"`
+
+	// 上次选中执行的命令及对应的位置参数
+	LastCommand string   `json:"last_command,omitempty"`
+	LastArgs    []string `json:"last_args,omitempty"`
+
+	// Pinned 为 true 时，参数与上次相同则直接使用上次的命令，不再请求模型
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// PinnedCommand 返回可以直接使用的固定命令，参数与上次不同时返回空字符串
+func (a *Alias) PinnedCommand(args []string) string {
+	if !a.Pinned || a.LastCommand == "" || !slices.Equal(a.LastArgs, args) {
+		return ""
+	}
+	return a.LastCommand
+}
+
+// GetAlias 返回指定名称的别名
+func (c *Config) GetAlias(name string) (*Alias, error) {
+	alias, ok := c.Aliases[name]
+	if !ok {
+		return nil, i18n.Errorf("别名不存在: %s", name)
+	}
+	return alias, nil
+}
+
+// AddAlias 添加或覆盖别名
+func (c *Config) AddAlias(name, prompt string) error {
+	slog.Debug("添加别名", "name", name, "prompt", prompt)
+	if c.Aliases == nil {
+		c.Aliases = make(map[string]*Alias)
+	}
+	c.Aliases[name] = &Alias{Prompt: prompt}
+	return c.SaveConfig()
+}
+
+// RemoveAlias 删除别名
+func (c *Config) RemoveAlias(name string) error {
+	if _, err := c.GetAlias(name); err != nil {
+		return err
+	}
+	slog.Debug("删除别名", "name", name)
+	delete(c.Aliases, name)
+	return c.SaveConfig()
+}
+
+// SetAliasLastCommand 记录别名上次选中执行的命令。固定了命令的别名保持不变，
+// 使用其他参数调用时不会改为固定新的参数和命令
+func (c *Config) SetAliasLastCommand(name string, args []string, command string) error {
+	alias, err := c.GetAlias(name)
+	if err != nil {
+		return err
+	}
+	if alias.Pinned {
+		slog.Debug("别名已固定命令，不记录本次执行的命令", "name", name, "args", args, "command", command)
+		return nil
+	}
+	slog.Debug("记录别名上次执行的命令", "name", name, "args", args, "command", command)
+	alias.LastCommand = command
+	alias.LastArgs = args
+	return c.SaveConfig()
+}
+
+// PinAlias 固定或取消固定别名上次执行的命令
+func (c *Config) PinAlias(name string, pinned bool) error {
+	alias, err := c.GetAlias(name)
+	if err != nil {
+		return err
+	}
+	if pinned && alias.LastCommand == "" {
+		return i18n.Errorf("别名 %s 还没有执行过命令，无法固定", name)
+	}
+	slog.Debug("设置别名固定状态", "name", name, "pinned", pinned)
+	alias.Pinned = pinned
+	return c.SaveConfig()
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetAliasLastCommandKeepsPinnedCommand(t *testing.T) {
+	oldDir := Dir()
+	SetDir(t.TempDir())
+	t.Cleanup(func() { SetDir(oldDir) })
+
+	cfg := &Config{}
+	if err := cfg.AddAlias("logs", "显示 {1} 服务的日志"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetAliasLastCommand("logs", []string{"nginx"}, "journalctl -u nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.PinAlias("logs", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// 使用其他参数调用固定的别名时，固定的参数和命令保持不变
+	if err := cfg.SetAliasLastCommand("logs", []string{"redis"}, "journalctl -u redis"); err != nil {
+		t.Fatal(err)
+	}
+	alias := cfg.Aliases["logs"]
+	if alias.LastCommand != "journalctl -u nginx" || !slices.Equal(alias.LastArgs, []string{"nginx"}) {
+		t.Errorf("alias = %+v, want the pinned nginx command", alias)
+	}
+	if got := alias.PinnedCommand([]string{"nginx"}); got != "journalctl -u nginx" {
+		t.Errorf("PinnedCommand(nginx) = %q", got)
+	}
+	if got := alias.PinnedCommand([]string{"redis"}); got != "" {
+		t.Errorf("PinnedCommand(redis) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"log/slog"
+
+	"AI-Shell/internal/i18n"
+)
+
+// CollectorConfig 单个上下文收集器的配置，未设置的字段使用收集器的默认值
+type CollectorConfig struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	Budget  int   `json:"budget,omitempty"`     // 输出的 token 预算，超出部分会被截断
+	Timeout int   `json:"timeout_ms,omitempty"` // 超时时间（毫秒），超时后跳过该收集器
+}
+
+// ExternalCollector 用户注册的外部收集器，执行脚本并将其标准输出作为上下文
+type ExternalCollector struct {
+	Name    string `json:"name"`
+	Command string `json:"command"` // 通过 sh -c 执行
+}
+
+// ListingConfig 目录列表收集器的设置
+type ListingConfig struct {
+	MaxEntries int  `json:"max_entries,omitempty"` // 条目数超过后只列出摘要和部分条目，0 表示使用默认值
+	Tree       bool `json:"tree,omitempty"`        // 列出深度为 2 的目录树，忽略 .gitignore 中的文件
+}
+
+// CollectorSettings 返回指定收集器的配置
+func (c *Config) CollectorSettings(name string) CollectorConfig {
+	return c.Collectors[name]
+}
+
+// SetCollectorEnabled 启用或禁用收集器
+func (c *Config) SetCollectorEnabled(name string, enabled bool) error {
+	slog.Debug("设置收集器", "name", name, "enabled", enabled)
+	settings := c.Collectors[name]
+	settings.Enabled = &enabled
+	c.setCollectorSettings(name, settings)
+	return c.SaveConfig()
+}
+
+// SetCollectorBudget 设置收集器的 token 预算
+func (c *Config) SetCollectorBudget(name string, budget int) error {
+	slog.Debug("设置收集器", "name", name, "budget", budget)
+	settings := c.Collectors[name]
+	settings.Budget = budget
+	c.setCollectorSettings(name, settings)
+	return c.SaveConfig()
+}
+
+// SetCollectorTimeout 设置收集器的超时时间（毫秒）
+func (c *Config) SetCollectorTimeout(name string, timeout int) error {
+	slog.Debug("设置收集器", "name", name, "timeout", timeout)
+	settings := c.Collectors[name]
+	settings.Timeout = timeout
+	c.setCollectorSettings(name, settings)
+	return c.SaveConfig()
+}
+
+// SetDefaultCollectorTimeout 设置收集器默认的超时时间（毫秒）
+func (c *Config) SetDefaultCollectorTimeout(timeout int) error {
+	slog.Debug("设置收集器默认超时时间", "timeout", timeout)
+	c.CollectorTimeout = timeout
+	return c.SaveConfig()
+}
+
+func (c *Config) setCollectorSettings(name string, settings CollectorConfig) {
+	if c.Collectors == nil {
+		c.Collectors = make(map[string]CollectorConfig)
+	}
+	c.Collectors[name] = settings
+}
+
+// AddExternalCollector 添加或覆盖外部收集器
+func (c *Config) AddExternalCollector(name, command string) error {
+	slog.Debug("添加外部收集器", "name", name, "command", command)
+	for i := range c.ExternalCollectors {
+		if c.ExternalCollectors[i].Name == name {
+			c.ExternalCollectors[i].Command = command
+			return c.SaveConfig()
+		}
+	}
+	c.ExternalCollectors = append(c.ExternalCollectors, ExternalCollector{Name: name, Command: command})
+	return c.SaveConfig()
+}
+
+// RemoveExternalCollector 删除外部收集器及其配置
+func (c *Config) RemoveExternalCollector(name string) error {
+	for i, external := range c.ExternalCollectors {
+		if external.Name == name {
+			slog.Debug("删除外部收集器", "name", name)
+			c.ExternalCollectors = append(c.ExternalCollectors[:i], c.ExternalCollectors[i+1:]...)
+			delete(c.Collectors, name)
+			return c.SaveConfig()
+		}
+	}
+	return i18n.Errorf("外部收集器不存在: %s", name)
+}
+
+// SetListingMaxEntries 设置目录列表的最大条目数
+func (c *Config) SetListingMaxEntries(count int) error {
+	slog.Debug("设置目录列表最大条目数", "count", count)
+	c.Listing.MaxEntries = count
+	return c.SaveConfig()
+}
+
+// SetListingTree 启用或禁用目录树
+func (c *Config) SetListingTree(tree bool) error {
+	slog.Debug("设置目录树", "tree", tree)
+	c.Listing.Tree = tree
+	return c.SaveConfig()
+}
+
+// SetHistoryEntries 设置 history 收集器读取的历史记录条数
+func (c *Config) SetHistoryEntries(count int) error {
+	slog.Debug("设置历史记录条数", "count", count)
+	c.HistoryEntries = count
+	return c.SaveConfig()
+}
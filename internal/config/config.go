@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+
+	"AI-Shell/internal/i18n"
 )
 
 // Config 存储应用程序的配置信息
@@ -16,6 +18,49 @@ type Config struct {
 	MaxTokens   int     `json:"max_tokens"`
 	Temperature float64 `json:"temperature"`
 	Debug       bool    `json:"debug"`
+	Prompt      string  `json:"prompt,omitempty"`
+	Language    string  `json:"language,omitempty"`
+
+	// Instructions 附加到系统提示中的额外规则
+	Instructions []string `json:"instructions,omitempty"`
+
+	// 候选命令设置，数量为 0 时使用默认值
+	MinCandidates    int  `json:"min_candidates,omitempty"`
+	MaxCandidates    int  `json:"max_candidates,omitempty"`
+	Diverse          bool `json:"diverse,omitempty"`            // 要求候选命令使用不同的工具或写法
+	SortByConfidence bool `json:"sort_by_confidence,omitempty"` // 按模型给出的置信度排序候选命令
+
+	Aliases map[string]*Alias `json:"aliases,omitempty"`
+
+	// 上下文收集器设置，键为收集器名称
+	Collectors         map[string]CollectorConfig `json:"collectors,omitempty"`
+	CollectorTimeout   int                        `json:"collector_timeout_ms,omitempty"` // 收集器默认的超时时间（毫秒），0 表示使用默认值
+	ExternalCollectors []ExternalCollector        `json:"external_collectors,omitempty"`
+	Listing            ListingConfig              `json:"listing"`
+	HistoryEntries     int                        `json:"history_entries,omitempty"` // history 收集器读取的历史记录条数，0 表示使用默认值
+
+	// Redact 发送前隐藏提示中的敏感信息
+	Redact RedactConfig `json:"redact"`
+
+	// Retry 请求失败时的重试设置
+	Retry RetryConfig `json:"retry"`
+
+	// Usage 令牌用量的价格表和月度预算
+	Usage UsageConfig `json:"usage"`
+
+	// Fallbacks 主模型因限流、服务端错误或网络故障不可用时，按顺序尝试的备用模型
+	Fallbacks []Fallback `json:"fallbacks,omitempty"`
+
+	// 请求时附加的 HTTP 头和合并到请求体中的字段，值中可以使用 ${NAME} 引用环境变量
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExtraBody map[string]any    `json:"extra_body,omitempty"`
+
+	// 访问 API 的网络设置，证书和私钥均为 PEM 格式的文件路径
+	Proxy              string `json:"proxy,omitempty"`                // 代理地址，支持 http、https 和 socks5，为空时使用 HTTPS_PROXY 等环境变量
+	CAFile             string `json:"ca_file,omitempty"`              // 在系统证书之外额外信任的 CA 证书
+	ClientCert         string `json:"client_cert,omitempty"`          // mTLS 客户端证书
+	ClientKey          string `json:"client_key,omitempty"`           // mTLS 客户端私钥
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // 跳过服务端证书校验，仅用于调试
 }
 
 const (
@@ -24,6 +69,11 @@ const (
 	DefaultMaxTokens   = 1000
 	DefaultTemperature = 0.7
 	DefaultDebug       = false // 默认不启用调试模式
+
+	DefaultMinCandidates = 1
+	DefaultMaxCandidates = 10
+
+	DefaultCollectorTimeout = 2000 // 毫秒
 )
 
 var (
@@ -34,7 +84,7 @@ var (
 func init() {
 	userConfigDir, err := os.UserConfigDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "获取用户配置目录失败: %v\n", err)
+		fmt.Fprintf(os.Stderr, i18n.T("获取用户配置目录失败: %v\n"), err)
 		os.Exit(1)
 	}
 
@@ -42,11 +92,22 @@ func init() {
 	configFile = filepath.Join(configDir, "ais_config.json")
 }
 
+// Dir 返回配置目录的路径
+func Dir() string {
+	return configDir
+}
+
+// SetDir 修改配置目录，用于测试时避免读写用户的配置
+func SetDir(dir string) {
+	configDir = dir
+	configFile = filepath.Join(dir, "ais_config.json")
+}
+
 // LoadConfig 加载配置文件，如果文件不存在则创建默认配置
 func LoadConfig() (*Config, error) {
 	// 确保配置目录存在
 	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("创建配置目录失败: %v", err)
+		return nil, i18n.Errorf("创建配置目录失败: %v", err)
 	}
 
 	// 如果配置文件不存在，创建默认配置
@@ -64,12 +125,12 @@ func LoadConfig() (*Config, error) {
 	// 读取配置文件
 	data, err := os.ReadFile(configFile)
 	if err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+		return nil, i18n.Errorf("读取配置文件失败: %v", err)
 	}
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+		return nil, i18n.Errorf("解析配置文件失败: %v", err)
 	}
 
 	// 如果配置文件中存在 debug 字段且为 true，则更新日志级别
@@ -83,11 +144,11 @@ func LoadConfig() (*Config, error) {
 func (c *Config) SaveConfig() error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
-		return fmt.Errorf("序列化配置失败: %v", err)
+		return i18n.Errorf("序列化配置失败: %v", err)
 	}
 
 	if err := os.WriteFile(configFile, data, 0644); err != nil {
-		return fmt.Errorf("保存配置文件失败: %v", err)
+		return i18n.Errorf("保存配置文件失败: %v", err)
 	}
 
 	return nil
@@ -135,3 +196,60 @@ func (c *Config) SetDebug(debug bool) error {
 	c.Debug = debug
 	return c.SaveConfig()
 }
+
+// SetPrompt 设置使用的内置提示模板，为空时跟随界面语言
+func (c *Config) SetPrompt(prompt string) error {
+	slog.Debug("设置配置项", "字段", "Prompt", "值", prompt)
+	c.Prompt = prompt
+	return c.SaveConfig()
+}
+
+// SetLanguage 设置界面语言，为空时根据环境变量自动检测
+func (c *Config) SetLanguage(language string) error {
+	slog.Debug("设置配置项", "字段", "Language", "值", language)
+	c.Language = language
+	return c.SaveConfig()
+}
+
+// CandidateRange 返回候选命令数量的上下限，未配置的一侧使用默认值
+func (c *Config) CandidateRange() (min, max int) {
+	min, max = c.MinCandidates, c.MaxCandidates
+	if min <= 0 {
+		min = DefaultMinCandidates
+	}
+	if max <= 0 {
+		max = DefaultMaxCandidates
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
+
+// SetMinCandidates 设置最少候选命令数
+func (c *Config) SetMinCandidates(count int) error {
+	slog.Debug("设置配置项", "字段", "MinCandidates", "值", count)
+	c.MinCandidates = count
+	return c.SaveConfig()
+}
+
+// SetMaxCandidates 设置最多候选命令数
+func (c *Config) SetMaxCandidates(count int) error {
+	slog.Debug("设置配置项", "字段", "MaxCandidates", "值", count)
+	c.MaxCandidates = count
+	return c.SaveConfig()
+}
+
+// SetDiverse 设置是否要求多样化的候选命令
+func (c *Config) SetDiverse(diverse bool) error {
+	slog.Debug("设置配置项", "字段", "Diverse", "值", diverse)
+	c.Diverse = diverse
+	return c.SaveConfig()
+}
+
+// SetSortByConfidence 设置是否按置信度排序候选命令
+func (c *Config) SetSortByConfidence(sortByConfidence bool) error {
+	slog.Debug("设置配置项", "字段", "SortByConfidence", "值", sortByConfidence)
+	c.SortByConfidence = sortByConfidence
+	return c.SaveConfig()
+}
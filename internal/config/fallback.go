@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log/slog"
+
+	"AI-Shell/internal/i18n"
+)
+
+// Fallback 为主模型不可用时依次尝试的备用模型。URL 为空时使用主配置的地址、密钥、请求头和附加字段，
+// 使用其他地址时只使用自己的 APIKey，APIKey 中可以使用 ${NAME} 引用环境变量
+type Fallback struct {
+	URL    string `json:"url,omitempty"`
+	APIKey string `json:"api_key,omitempty"`
+	Model  string `json:"model"`
+}
+
+// Resolve 返回使用备用模型的配置副本，其余设置与 c 相同。
+// 备用模型使用其他地址时，不使用主配置的密钥、请求头和附加字段，避免把认证信息发送给其他服务
+func (c *Config) Resolve(fallback Fallback) *Config {
+	resolved := *c
+	resolved.Model = fallback.Model
+	if fallback.URL != "" && fallback.URL != c.URL {
+		resolved.URL = fallback.URL
+		resolved.APIKey = ""
+		resolved.Headers, resolved.ExtraBody = nil, nil
+	}
+	if fallback.APIKey != "" {
+		resolved.APIKey = ExpandEnv(fallback.APIKey)
+	}
+	resolved.Fallbacks = nil
+	return &resolved
+}
+
+// AddFallback 将备用模型追加到列表末尾
+func (c *Config) AddFallback(fallback Fallback) error {
+	if fallback.Model == "" {
+		return i18n.Errorf("备用模型的名称不能为空")
+	}
+	slog.Debug("添加备用模型", "model", fallback.Model, "url", fallback.URL)
+	c.Fallbacks = append(c.Fallbacks, fallback)
+	return c.SaveConfig()
+}
+
+// RemoveFallback 删除第 index 个备用模型，index 从 1 开始
+func (c *Config) RemoveFallback(index int) error {
+	if index < 1 || index > len(c.Fallbacks) {
+		return i18n.Errorf("备用模型不存在: %d", index)
+	}
+	slog.Debug("删除备用模型", "index", index, "model", c.Fallbacks[index-1].Model)
+	c.Fallbacks = append(c.Fallbacks[:index-1], c.Fallbacks[index:]...)
+	return c.SaveConfig()
+}
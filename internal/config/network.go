@@ -0,0 +1,31 @@
+package config
+
+import "log/slog"
+
+// SetProxy 设置访问 API 使用的代理，为空时使用 HTTPS_PROXY 等环境变量
+func (c *Config) SetProxy(proxy string) error {
+	slog.Debug("设置代理", "proxy", proxy)
+	c.Proxy = proxy
+	return c.SaveConfig()
+}
+
+// SetCAFile 设置额外信任的 CA 证书文件，为空时只信任系统证书
+func (c *Config) SetCAFile(path string) error {
+	slog.Debug("设置 CA 证书", "path", path)
+	c.CAFile = path
+	return c.SaveConfig()
+}
+
+// SetClientCert 设置 mTLS 使用的客户端证书和私钥，均为空时不使用客户端证书
+func (c *Config) SetClientCert(cert, key string) error {
+	slog.Debug("设置客户端证书", "cert", cert, "key", key)
+	c.ClientCert, c.ClientKey = cert, key
+	return c.SaveConfig()
+}
+
+// SetInsecureSkipVerify 设置是否跳过服务端证书校验
+func (c *Config) SetInsecureSkipVerify(skip bool) error {
+	slog.Debug("设置跳过证书校验", "skip", skip)
+	c.InsecureSkipVerify = skip
+	return c.SaveConfig()
+}
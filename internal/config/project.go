@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"AI-Shell/internal/i18n"
+)
+
+const (
+	ProjectConfigFile  = ".ais.json"   // 项目根目录下的单文件配置
+	ProjectConfigDir   = ".ais"        // 项目根目录下的配置目录
+	projectDirConfig   = "config.json" // .ais/ 目录中的配置文件
+	projectContextFile = "context.md"  // .ais/ 目录中的项目上下文文件
+)
+
+// ProjectConfig 存储项目级配置，用于覆盖全局配置中的部分字段。
+// 出于安全考虑，项目配置不能覆盖 URL 和 APIKey，
+// 否则克隆下来的仓库就可以把用户的密钥发往任意地址。
+type ProjectConfig struct {
+	Model        string   `json:"model,omitempty"`
+	MaxTokens    int      `json:"max_tokens,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+	Context      string   `json:"context,omitempty"`
+
+	// Path 为找到的项目配置文件或目录的路径
+	Path string `json:"-"`
+}
+
+// FindProjectConfig 从 dir 开始逐级向上查找 .ais.json 或 .ais/ 目录，
+// 返回离 dir 最近的项目配置；没有找到时返回 nil。
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, i18n.Errorf("解析目录路径失败: %v", err)
+	}
+
+	for {
+		filePath := filepath.Join(dir, ProjectConfigFile)
+		if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+			slog.Debug("找到项目配置文件", "path", filePath)
+			return loadProjectFile(filePath)
+		}
+
+		dirPath := filepath.Join(dir, ProjectConfigDir)
+		if info, err := os.Stat(dirPath); err == nil && info.IsDir() {
+			slog.Debug("找到项目配置目录", "path", dirPath)
+			return loadProjectDir(dirPath)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// loadProjectFile 读取单文件形式的项目配置
+func loadProjectFile(path string) (*ProjectConfig, error) {
+	project := &ProjectConfig{Path: path}
+	if err := readProjectJSON(path, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// loadProjectDir 读取目录形式的项目配置：config.json 与 context.md 均为可选
+func loadProjectDir(path string) (*ProjectConfig, error) {
+	project := &ProjectConfig{Path: path}
+
+	configPath := filepath.Join(path, projectDirConfig)
+	if _, err := os.Stat(configPath); err == nil {
+		if err := readProjectJSON(configPath, project); err != nil {
+			return nil, err
+		}
+	}
+
+	contextPath := filepath.Join(path, projectContextFile)
+	data, err := os.ReadFile(contextPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, i18n.Errorf("读取项目上下文失败: %v", err)
+	}
+	if len(data) > 0 {
+		project.Context = joinNonEmpty(project.Context, string(data))
+	}
+
+	return project, nil
+}
+
+func readProjectJSON(path string, project *ProjectConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.Errorf("读取项目配置失败: %v", err)
+	}
+	if err := json.Unmarshal(data, project); err != nil {
+		return i18n.Errorf("解析项目配置失败 %s: %v", path, err)
+	}
+	return nil
+}
+
+// WithProject 返回合并了项目配置的配置副本。
+// 返回的是副本，保存全局配置时不会把项目覆盖项写回全局配置文件。
+func (c *Config) WithProject(project *ProjectConfig) *Config {
+	merged := *c
+	merged.Instructions = append([]string(nil), c.Instructions...)
+	if project == nil {
+		return &merged
+	}
+
+	if project.Model != "" {
+		merged.Model = project.Model
+	}
+	if project.MaxTokens > 0 {
+		merged.MaxTokens = project.MaxTokens
+	}
+	if project.Temperature != nil {
+		merged.Temperature = *project.Temperature
+	}
+	merged.Instructions = append(merged.Instructions, project.Instructions...)
+
+	return &merged
+}
+
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			kept = append(kept, trimmed)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
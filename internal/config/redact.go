@@ -0,0 +1,18 @@
+package config
+
+import "log/slog"
+
+// RedactConfig 发送给 API 之前的脱敏设置，默认启用
+type RedactConfig struct {
+	Disabled     bool     `json:"disabled,omitempty"`
+	Patterns     []string `json:"patterns,omitempty"`      // 额外的敏感内容正则
+	DenyFiles    []string `json:"deny_files,omitempty"`    // 额外的敏感文件名模式，如 *.secret
+	HashUsername bool     `json:"hash_username,omitempty"` // 用户名替换为稳定的哈希而不是 <user>
+}
+
+// SetRedact 启用或禁用脱敏
+func (c *Config) SetRedact(enabled bool) error {
+	slog.Debug("设置脱敏", "enabled", enabled)
+	c.Redact.Disabled = !enabled
+	return c.SaveConfig()
+}
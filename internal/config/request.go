@@ -0,0 +1,73 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"regexp"
+
+	"AI-Shell/internal/i18n"
+)
+
+// envPattern 匹配 ${NAME} 形式的环境变量引用。不支持 $NAME 形式，避免误伤值中的 $
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv 将 value 中的 ${NAME} 替换为环境变量的值，未设置的环境变量替换为空字符串并发出警告
+func ExpandEnv(value string) string {
+	return envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := envPattern.FindStringSubmatch(match)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			slog.Warn(i18n.Sprintf("环境变量 %s 未设置", name))
+		}
+		return env
+	})
+}
+
+// ExpandEnvValue 递归替换 JSON 值中所有字符串里的环境变量引用
+func ExpandEnvValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return ExpandEnv(v)
+	case map[string]any:
+		expanded := make(map[string]any, len(v))
+		for key, item := range v {
+			expanded[key] = ExpandEnvValue(item)
+		}
+		return expanded
+	case []any:
+		expanded := make([]any, len(v))
+		for i, item := range v {
+			expanded[i] = ExpandEnvValue(item)
+		}
+		return expanded
+	}
+	return value
+}
+
+// SetHeader 设置请求时附加的 HTTP 头，value 为空时删除
+func (c *Config) SetHeader(name, value string) error {
+	slog.Debug("设置请求头", "name", name)
+	if value == "" {
+		delete(c.Headers, name)
+	} else {
+		if c.Headers == nil {
+			c.Headers = make(map[string]string)
+		}
+		c.Headers[name] = value
+	}
+	return c.SaveConfig()
+}
+
+// SetExtraBody 设置合并到请求体中的字段，value 为 nil 时删除
+func (c *Config) SetExtraBody(key string, value any) error {
+	slog.Debug("设置请求体字段", "key", key, "value", value)
+	if value == nil {
+		delete(c.ExtraBody, key)
+	} else {
+		if c.ExtraBody == nil {
+			c.ExtraBody = make(map[string]any)
+		}
+		c.ExtraBody[key] = value
+	}
+	return c.SaveConfig()
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = 500   // 毫秒
+	DefaultRetryMaxDelay  = 20000 // 毫秒
+)
+
+// RetryConfig 请求失败时的重试设置，未设置的字段使用默认值
+type RetryConfig struct {
+	MaxRetries *int `json:"max_retries,omitempty"`   // 最多重试次数，0 表示不重试
+	BaseDelay  int  `json:"base_delay_ms,omitempty"` // 第一次重试前的等待时间（毫秒），之后每次翻倍
+	MaxDelay   int  `json:"max_delay_ms,omitempty"`  // 两次重试之间最长的等待时间（毫秒）
+}
+
+// RetrySettings 返回合并了默认值后的重试次数和等待时间
+func (c *Config) RetrySettings() (maxRetries int, baseDelay, maxDelay time.Duration) {
+	maxRetries = DefaultMaxRetries
+	if c.Retry.MaxRetries != nil {
+		maxRetries = *c.Retry.MaxRetries
+	}
+	baseDelay, maxDelay = DefaultRetryBaseDelay*time.Millisecond, DefaultRetryMaxDelay*time.Millisecond
+	if c.Retry.BaseDelay > 0 {
+		baseDelay = time.Duration(c.Retry.BaseDelay) * time.Millisecond
+	}
+	if c.Retry.MaxDelay > 0 {
+		maxDelay = time.Duration(c.Retry.MaxDelay) * time.Millisecond
+	}
+	return maxRetries, baseDelay, maxDelay
+}
+
+// SetMaxRetries 设置最多重试次数
+func (c *Config) SetMaxRetries(maxRetries int) error {
+	slog.Debug("设置重试次数", "maxRetries", maxRetries)
+	c.Retry.MaxRetries = &maxRetries
+	return c.SaveConfig()
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const DefaultCurrency = "USD"
+
+// ModelPrice 模型的价格，单位为每百万 tokens
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// UsageConfig 令牌用量统计的价格表和月度预算，预算为 0 表示不限制
+type UsageConfig struct {
+	Prices     map[string]ModelPrice `json:"prices,omitempty"`      // 键为模型名称或模型名称前缀
+	Currency   string                `json:"currency,omitempty"`    // 价格和预算的货币单位，仅用于显示
+	SoftBudget float64               `json:"soft_budget,omitempty"` // 本月费用超过后发出警告
+	HardBudget float64               `json:"hard_budget,omitempty"` // 本月费用达到后拒绝发送请求
+}
+
+// Price 返回模型的价格。没有完全匹配的模型时使用最长的前缀匹配，
+// 这样 gpt-4o-mini 的价格也适用于 gpt-4o-mini-2024-07-18
+func (u UsageConfig) Price(model string) (ModelPrice, bool) {
+	if price, ok := u.Prices[model]; ok {
+		return price, true
+	}
+	var matched string
+	for name := range u.Prices {
+		if strings.HasPrefix(model, name) && len(name) > len(matched) {
+			matched = name
+		}
+	}
+	if matched == "" {
+		return ModelPrice{}, false
+	}
+	return u.Prices[matched], true
+}
+
+// Cost 按价格表计算一次请求的费用，没有设置价格时返回 false
+func (u UsageConfig) Cost(model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := u.Price(model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6, true
+}
+
+// CurrencyOrDefault 返回货币单位，未设置时为 USD
+func (u UsageConfig) CurrencyOrDefault() string {
+	if u.Currency == "" {
+		return DefaultCurrency
+	}
+	return u.Currency
+}
+
+// SetModelPrice 设置模型每百万 tokens 的输入和输出价格
+func (c *Config) SetModelPrice(model string, input, output float64) error {
+	slog.Debug("设置模型价格", "model", model, "input", input, "output", output)
+	if c.Usage.Prices == nil {
+		c.Usage.Prices = make(map[string]ModelPrice)
+	}
+	c.Usage.Prices[model] = ModelPrice{Input: input, Output: output}
+	return c.SaveConfig()
+}
+
+// SetSoftBudget 设置月度软性预算，0 表示不限制
+func (c *Config) SetSoftBudget(budget float64) error {
+	slog.Debug("设置月度软性预算", "budget", budget)
+	c.Usage.SoftBudget = budget
+	return c.SaveConfig()
+}
+
+// SetHardBudget 设置月度硬性预算，0 表示不限制
+func (c *Config) SetHardBudget(budget float64) error {
+	slog.Debug("设置月度硬性预算", "budget", budget)
+	c.Usage.HardBudget = budget
+	return c.SaveConfig()
+}
@@ -0,0 +1,532 @@
+package i18n
+
+// english 为英文译文目录表，键为代码中的中文原文
+var english = map[string]string{
+	// 通用
+	"执行错误: %v\n":                "error: %v\n",
+	"加载配置失败: %v":                "failed to load config: %v",
+	"加载配置失败: %w":                "failed to load config: %w",
+	"加载项目配置失败: %v":              "failed to load project config: %v",
+	"序列化配置失败: %v":               "failed to serialize config: %v",
+	"不支持的语言: %s (可用: auto, %s)": "unsupported language: %s (available: auto, %s)",
+
+	// ais
+	"AI-Shell - 基于 OpenAI 的命令行工具": "AI-Shell - an OpenAI powered command line tool",
+	`AI-Shell 是一个基于 OpenAI 的自然语言处理能力开发的命令行工具，
+旨在简化 Linux 命令的使用。通过 AI-Shell，用户可以通过自然语言直接
+在终端中执行复杂的 Linux 命令，而无需记住繁琐的命令语法。`: `AI-Shell is a command line tool built on the natural language abilities of OpenAI
+models to make Linux commands easier to use. Describe what you want in plain
+language and run complex commands in your terminal without memorizing their syntax.`,
+	"显示发送到API的数据":   "show the data sent to the API",
+	"激活 debug 日志模式": "enable debug logging",
+
+	// ais exec
+	"执行自然语言命令":              "run a natural language command",
+	"将自然语言描述转换为命令行命令并执行。":   "Translate a natural language description into a shell command and run it.",
+	"获取系统信息失败: %v":          "failed to collect system information: %v",
+	"获取show-data标志失败: %v":   "failed to read the show-data flag: %v",
+	"渲染系统提示失败: %v":          "failed to render the system prompt: %v",
+	"发送请求失败: %v":            "failed to send request: %v",
+	"未收到有效响应":               "no valid response received",
+	"解析响应失败: %v":            "failed to parse response: %v",
+	"=== 请求和响应数据 ===":       "=== Request and response data ===",
+	"发送数据:":                 "Request:",
+	"格式化请求数据失败: %v":         "failed to format request data: %v",
+	"\n响应数据:":               "\nResponse:",
+	"格式化响应数据失败: %v":         "failed to format response data: %v",
+	"\n解析后的AI响应:":           "\nParsed AI response:",
+	"格式化AI响应数据失败: %v":       "failed to format AI response data: %v",
+	"可用的命令选项:":              "Available commands:",
+	"命令翻译失败: %s (code: %d)": "translation failed: %s (code: %d)",
+	"0: 退出":                 "0: quit",
+	"请选择要执行的命令: ":           "Choose a command to run: ",
+	"无效的选择":                 "invalid choice",
+	"退出程序。":                 "Bye.",
+	"执行命令: %s\n":            "Running: %s\n",
+	"命令执行失败: %v":            "command failed: %v",
+
+	// ais config
+	"配置管理命令": "manage configuration",
+	"管理 AI-Shell 的配置，包括API URL、密钥、模型等设置。": "Manage AI-Shell settings such as the API URL, key and model.",
+	"设置配置项": "set a configuration value",
+	"设置各种配置项的值，包括API URL、密钥、模型等。": "Set configuration values such as the API URL, key and model.",
+	"查看当前配置":                   "show the current configuration",
+	"显示所有当前配置项的值。":             "Show the values of all configuration items.",
+	"当前配置:\n":                  "Current configuration:\n",
+	"\n项目配置 (%s):\n":           "\nProject configuration (%s):\n",
+	"设置API URL":                "set the API URL",
+	"设置OpenAI API的URL地址。":      "Set the URL of the OpenAI compatible API.",
+	"设置URL失败: %v":              "failed to set URL: %v",
+	"已设置 URL = %s\n":           "URL = %s\n",
+	"设置API密钥":                  "set the API key",
+	"设置OpenAI API的访问密钥。":       "Set the access key of the OpenAI compatible API.",
+	"设置API密钥失败: %v":            "failed to set API key: %v",
+	"已设置 API_KEY = %s\n":       "API_KEY = %s\n",
+	"设置模型":                     "set the model",
+	"设置使用的AI模型名称。":             "Set the name of the AI model to use.",
+	"设置模型失败: %v":               "failed to set model: %v",
+	"已设置 MODEL = %s\n":         "MODEL = %s\n",
+	"设置最大令牌数":                  "set max tokens",
+	"设置API请求的最大令牌数。":           "Set the maximum number of tokens per API request.",
+	"无效的最大令牌数值: %v":            "invalid max tokens value: %v",
+	"设置最大令牌数失败: %v":            "failed to set max tokens: %v",
+	"已设置 MAX_TOKENS = %d\n":    "MAX_TOKENS = %d\n",
+	"设置温度参数":                   "set the temperature",
+	"设置生成文本的随机性，范围从0到1。":       "Set the randomness of generated text, from 0 to 1.",
+	"无效的温度参数值: %v":             "invalid temperature value: %v",
+	"温度参数必须在0到1之间":             "temperature must be between 0 and 1",
+	"设置温度参数失败: %v":             "failed to set temperature: %v",
+	"已设置 TEMPERATURE = %.1f\n": "TEMPERATURE = %.1f\n",
+	"设置调试模式":                   "set debug mode",
+	"启用或禁用调试模式，调试模式下会输出更多的日志信息。": "Enable or disable debug mode, which prints more log output.",
+	"无效的Debug模式值: %v":     "invalid debug value: %v",
+	"设置Debug模式失败: %v":     "failed to set debug mode: %v",
+	"已设置 Debug 模式 = %v\n": "DEBUG = %v\n",
+	"设置界面语言":              "set the interface language",
+	"设置界面和模型回复使用的语言，auto 表示根据 LANG、LC_MESSAGES 环境变量自动检测。": "Set the language of the interface and of model replies. auto detects it from the LANG and LC_MESSAGES environment variables.",
+	"设置语言失败: %v":          "failed to set language: %v",
+	"已设置 LANGUAGE = %s\n": "LANGUAGE = %s\n",
+
+	// ais prompt
+	"系统提示模板管理命令": "manage the system prompt template",
+	`管理发送给模型的系统提示模板。
+
+模板使用 Go text/template 语法，可用变量:
+  {{.SystemInfo}}     收集到的系统信息
+  {{.Shell}}          当前 shell 名称
+  {{.Locale}}         当前语言环境
+  {{.Language}}       模型回复 msg 时使用的语言
+  {{.MinCandidates}}  最少返回的候选命令数
+  {{.MaxCandidates}}  最多返回的候选命令数
+  {{.Diverse}}        是否要求多样化的候选命令
+  {{.Confidence}}     是否要求模型给出置信度
+  {{.Rules}}          用户配置和项目配置中的额外规则`: `Manage the system prompt template sent to the model.
+
+Templates use Go text/template syntax. Available variables:
+  {{.SystemInfo}}     collected system information
+  {{.Shell}}          name of the current shell
+  {{.Locale}}         current locale
+  {{.Language}}       language the model writes msg in
+  {{.MinCandidates}}  minimum number of candidate commands
+  {{.MaxCandidates}}  maximum number of candidate commands
+  {{.Diverse}}        whether diverse candidates are requested
+  {{.Confidence}}     whether the model reports confidence
+  {{.Rules}}          extra rules from the user and project config`,
+	"显示当前生效的提示模板":                         "show the active prompt template",
+	"显示当前生效的提示模板，使用 --render 显示渲染后的系统提示。": "Show the active prompt template. Use --render to show the rendered system prompt.",
+	"显示渲染后的系统提示":                          "show the rendered system prompt",
+	"编辑自定义提示模板":                           "edit the custom prompt template",
+	"使用 $VISUAL 或 $EDITOR 编辑自定义提示模板，不存在时以当前内置模板为初始内容。": "Edit the custom prompt template with $VISUAL or $EDITOR. If it does not exist yet, it starts from the current built-in template.",
+	"恢复内置提示模板": "restore the built-in prompt template",
+	"删除自定义提示模板，恢复使用内置提示模板。": "Delete the custom prompt template and go back to the built-in one.",
+	"设置内置提示模板":                     "set the built-in prompt template",
+	"设置没有自定义模板时使用的内置提示模板。":         "Set the built-in prompt template used when there is no custom template.",
+	"# 自定义模板: %s\n":                "# custom template: %s\n",
+	"# 内置模板: %s\n":                 "# built-in template: %s\n",
+	"创建自定义模板失败: %v":                "failed to create custom template: %v",
+	"启动编辑器失败: %v":                  "failed to start editor: %v",
+	"读取自定义模板失败: %v":                "failed to read custom template: %v",
+	"%v，请重新编辑或执行 ais prompt reset": "%v, edit it again or run ais prompt reset",
+	"已保存自定义模板: %s\n":               "Saved custom template: %s\n",
+	"已恢复内置提示模板":                    "Restored the built-in prompt template",
+	"设置提示模板失败: %v":                 "failed to set prompt template: %v",
+	"已设置 PROMPT = %s\n":            "PROMPT = %s\n",
+	"内置模板不存在: %s (可用: %s)":         "no such built-in template: %s (available: %s)",
+	"渲染提示模板失败: %v":                 "failed to render prompt template: %v",
+	"解析提示模板失败: %v":                 "failed to parse prompt template: %v",
+	"删除自定义模板失败: %v":                "failed to delete custom template: %v",
+
+	// internal/config
+	"获取用户配置目录失败: %v\n": "failed to get the user config directory: %v\n",
+	"创建配置目录失败: %v":     "failed to create config directory: %v",
+	"读取配置文件失败: %v":     "failed to read config file: %v",
+	"解析配置文件失败: %v":     "failed to parse config file: %v",
+	"保存配置文件失败: %v":     "failed to save config file: %v",
+	"解析目录路径失败: %v":     "failed to resolve directory path: %v",
+	"读取项目上下文失败: %v":    "failed to read project context: %v",
+	"读取项目配置失败: %v":     "failed to read project config: %v",
+	"解析项目配置失败 %s: %v":  "failed to parse project config %s: %v",
+
+	// internal/system
+	"读取系统信息失败: %v":   "failed to read OS information: %v",
+	"解析系统信息失败: %v":   "failed to parse OS information: %v",
+	"获取用户ID失败: %v":   "failed to get user ID: %v",
+	"获取当前工作目录失败: %v": "failed to get working directory: %v",
+	"读取目录内容失败: %v":   "failed to read directory: %v",
+
+	// internal/openai
+	"序列化请求失败: %v": "failed to serialize request: %v",
+	"创建请求失败: %v":  "failed to create request: %v",
+
+	// 候选命令
+	"候选命令数必须是正整数: %s":              "the number of candidates must be a positive integer: %s",
+	"最少候选命令数不能大于最多候选命令数 %d":        "the minimum number of candidates cannot exceed the maximum %d",
+	"最多候选命令数不能小于最少候选命令数 %d":        "the maximum number of candidates cannot be below the minimum %d",
+	"设置候选命令数失败: %v":                "failed to set the number of candidates: %v",
+	"已设置 MIN_CANDIDATES = %d\n":    "MIN_CANDIDATES = %d\n",
+	"已设置 MAX_CANDIDATES = %d\n":    "MAX_CANDIDATES = %d\n",
+	"无效的布尔值: %v":                   "invalid boolean value: %v",
+	"设置多样化候选命令失败: %v":              "failed to set diverse candidates: %v",
+	"已设置 DIVERSE = %v\n":           "DIVERSE = %v\n",
+	"设置按置信度排序失败: %v":               "failed to set confidence sorting: %v",
+	"已设置 SORT_CONFIDENCE = %v\n":   "SORT_CONFIDENCE = %v\n",
+	"设置最少候选命令数":                    "set the minimum number of candidates",
+	"设置模型最少返回的候选命令数。":              "Set the minimum number of candidate commands the model returns.",
+	"设置最多候选命令数":                    "set the maximum number of candidates",
+	"设置模型最多返回的候选命令数，设置为1时只返回一条命令。": "Set the maximum number of candidate commands the model returns. Set it to 1 to get a single command.",
+	"设置多样化候选命令":                    "set diverse candidates",
+	"启用后要求模型给出使用不同工具或写法的候选命令，例如可移植写法和 GNU 专用写法。": "When enabled, ask the model for candidates that use different tools or approaches, such as portable and GNU-specific versions.",
+	"设置按置信度排序": "set confidence sorting",
+	"启用后要求模型给出每条候选命令的置信度，并按置信度从高到低排序。": "When enabled, ask the model for a confidence score per candidate and sort candidates from most to least confident.",
+	"恰好返回的候选命令数，等同于同时设置最少和最多候选命令数":     "exact number of candidates, same as setting both the minimum and the maximum",
+	"最少返回的候选命令数":       "minimum number of candidates",
+	"最多返回的候选命令数":       "maximum number of candidates",
+	"要求候选命令使用不同的工具或写法": "ask for candidates that use different tools or approaches",
+	"按模型给出的置信度排序候选命令":  "sort candidates by the confidence the model reports",
+
+	// ais alias
+	"使用固定的命令:":                  "Using the pinned command:",
+	"缺少别名参数 %s":                 "missing alias argument %s",
+	"无效的别名名称: %s":               "invalid alias name: %s",
+	"添加别名失败: %v":                "failed to add alias: %v",
+	"已添加别名 %s%s = %s\n":         "Added alias %s%s = %s\n",
+	"还没有别名，使用 ais alias add 添加": "No aliases yet, add one with ais alias add",
+	"    固定命令: %s\n":            "    pinned: %s\n",
+	"    上次命令: %s\n":            "    last: %s\n",
+	"已删除别名 %s%s\n":              "Removed alias %s%s\n",
+	"已固定别名 %s%s 的命令: %s\n":      "Pinned the command of alias %s%s: %s\n",
+	"已取消固定别名 %s%s 的命令\n":        "Unpinned the command of alias %s%s\n",
+	"别名管理命令":                    "manage saved prompts",
+	`管理保存的提示，保存后可以通过 ais @名称 [参数...] 调用。
+
+提示中可以使用 {1}、{2} 等占位符引用调用时传入的位置参数。`: `Manage saved prompts, which can be run with ais @name [args...].
+
+Prompts can use placeholders such as {1} and {2} to refer to positional arguments.`,
+	"添加别名": "add an alias",
+	"添加或覆盖一个别名，例如: ais alias add logs \"显示 {1} 服务最近 200 行带时间戳的日志\"": "Add or replace an alias, for example: ais alias add logs \"show the last 200 lines of the {1} service journal with timestamps\"",
+	"列出别名": "list aliases",
+	"列出所有别名及其上次执行的命令。": "List all aliases and the command each one ran last.",
+	"删除别名":     "remove an alias",
+	"删除指定的别名。": "Remove the given alias.",
+	"固定别名的命令":  "pin the command of an alias",
+	"固定别名上次选中执行的命令，之后使用相同参数调用时直接执行该命令，不再请求模型。": "Pin the command last chosen for an alias. Later calls with the same arguments run it directly without asking the model.",
+	"取消固定别名的命令":              "unpin the command of an alias",
+	"取消固定别名的命令，之后调用时重新请求模型。": "Unpin the command of an alias so later calls ask the model again.",
+	"别名不存在: %s":              "no such alias: %s",
+	"别名 %s 还没有执行过命令，无法固定":    "alias %s has not run a command yet, nothing to pin",
+
+	// ais collector
+	"收集器不存在: %s": "no such collector: %s",
+	"已启用":        "enabled",
+	"已禁用":        "disabled",
+	"内置":         "builtin",
+	"外部":         "external",
+	"%-10s %s  %s  预算 %d tokens  超时 %v\n": "%-10s %s  %s  budget %d tokens  timeout %v\n",
+	"设置收集器失败: %v":                         "failed to update collector: %v",
+	"已启用收集器 %s\n":                         "Enabled collector %s\n",
+	"已禁用收集器 %s\n":                         "Disabled collector %s\n",
+	"token 预算必须是正整数: %s":                  "the token budget must be a positive integer: %s",
+	"已设置收集器 %s 的预算 = %d tokens\n":         "Budget of collector %s = %d tokens\n",
+	"不能覆盖内置收集器: %s":                       "cannot replace builtin collector: %s",
+	"添加外部收集器失败: %v":                       "failed to add external collector: %v",
+	"已添加外部收集器 %s = %s\n":                  "Added external collector %s = %s\n",
+	"已删除外部收集器 %s\n":                       "Removed external collector %s\n",
+	"上下文收集器管理命令":                          "manage context collectors",
+	`管理发送给模型的上下文收集器。
+
+每个收集器负责一类上下文信息，例如 shell、系统信息、目录内容等，
+可以单独启用或禁用，并设置输出的 token 预算。
+也可以添加外部收集器，外部收集器通过 sh -c 执行脚本，并将标准输出作为上下文。`: `Manage the context collectors whose output is sent to the model.
+
+Each collector provides one kind of context, such as the shell, OS information or the
+directory listing. Collectors can be enabled or disabled one by one and have a token budget.
+External collectors run a script with sh -c and use its standard output as context.`,
+	"列出收集器": "list collectors",
+	"列出所有收集器及其启用状态和 token 预算。": "List all collectors with their status and token budget.",
+	"启用收集器":           "enable a collector",
+	"启用指定的收集器。":       "Enable the given collector.",
+	"禁用收集器":           "disable a collector",
+	"禁用指定的收集器。":       "Disable the given collector.",
+	"设置收集器的 token 预算": "set the token budget of a collector",
+	"设置收集器输出的 token 预算，超出部分会被截断。": "Set the token budget of a collector. Output beyond the budget is truncated.",
+	"添加外部收集器": "add an external collector",
+	"添加外部收集器，例如: ais collector add k8s \"kubectl config current-context\"": "Add an external collector, for example: ais collector add k8s \"kubectl config current-context\"",
+	"删除外部收集器":           "remove an external collector",
+	"删除指定的外部收集器。":       "Remove the given external collector.",
+	"外部收集器不存在: %s":      "no such external collector: %s",
+	"执行外部收集器 %s 失败: %v": "external collector %s failed: %v",
+	"...(已截断)":          "...(truncated)",
+
+	// internal/system git
+	"读取 git HEAD 失败: %v": "failed to read git HEAD: %v",
+
+	// 候选命令可用性
+	"[未安装: %s]": "[not installed: %s]",
+
+	// 脱敏
+	"创建脱敏规则失败: %v":      "failed to create redaction rules: %v",
+	"脱敏已禁用":             "Redaction is disabled",
+	"已隐藏 %d 处敏感信息\n":    "Redacted %d sensitive item(s)\n",
+	"设置脱敏失败: %v":        "failed to set redaction: %v",
+	"已设置 REDACT = %v\n": "REDACT set to %v\n",
+	"设置脱敏":              "Set redaction",
+	"启用后在发送给 API 之前隐藏密钥和令牌、用户主目录、用户名以及 .env、*.pem 等敏感文件名。默认启用。": "When enabled, secrets and tokens, the home directory, the username and sensitive file names such as .env and *.pem are hidden before anything is sent to the API. Enabled by default.",
+	"无效的脱敏规则 %s: %v":  "invalid redaction pattern %s: %v",
+	"无效的文件名模式 %s: %v": "invalid file name pattern %s: %v",
+
+	// 目录列表
+	"条目数必须是非负整数: %s":          "the number of entries must be a non-negative integer: %s",
+	"设置目录列表最大条目数失败: %v":       "failed to set the maximum number of listing entries: %v",
+	"已设置 LISTING_MAX = %d\n":  "LISTING_MAX set to %d\n",
+	"设置目录树失败: %v":             "failed to set the directory tree: %v",
+	"已设置 LISTING_TREE = %v\n": "LISTING_TREE set to %v\n",
+	"设置目录列表最大条目数":             "Set the maximum number of listing entries",
+	"设置发送给模型的目录列表最多包含的条目数，超过后只发送按类型和扩展名的统计以及部分条目，0 表示使用默认值。": "Set the maximum number of entries in the directory listing sent to the model. Larger directories are sent as counts by type and extension plus a subset of entries. 0 means the default.",
+	"设置目录树": "Set the directory tree",
+	"启用后发送深度为 2 的目录树，.gitignore 中忽略的文件不会列出。": "When enabled, a directory tree of depth 2 is sent. Files ignored by .gitignore are not listed.",
+
+	// 历史记录
+	"设置历史记录条数失败: %v":             "failed to set the number of history entries: %v",
+	"已设置 HISTORY_ENTRIES = %d\n": "HISTORY_ENTRIES set to %d\n",
+	"设置历史记录条数":                   "Set the number of history entries",
+	"设置 history 收集器发送给模型的最近历史记录条数，0 表示使用默认值。history 收集器默认禁用，使用 ais collector enable history 启用。": "Set how many recent history entries the history collector sends to the model. 0 means the default. The history collector is disabled by default; enable it with ais collector enable history.",
+	"读取历史记录失败: %v": "failed to read shell history: %v",
+
+	// 收集器超时
+	"超时时间必须是正整数: %s":            "the timeout must be a positive integer: %s",
+	"已设置收集器 %s 的超时时间 = %d ms\n": "Timeout of collector %s set to %d ms\n",
+	"设置收集器的超时时间":                "Set the timeout of a collector",
+	"设置收集器的超时时间（毫秒），超时的收集器会被跳过，不会阻塞请求。默认超时时间可以通过 ais config set collector-timeout 设置。": "Set the timeout of a collector in milliseconds. A collector that times out is skipped and does not block the request. The default timeout can be set with ais config set collector-timeout.",
+	"超时时间必须是非负整数: %s":                 "the timeout must be a non-negative integer: %s",
+	"设置收集器默认超时时间失败: %v":               "failed to set the default collector timeout: %v",
+	"已设置 COLLECTOR_TIMEOUT = %d ms\n": "COLLECTOR_TIMEOUT set to %d ms\n",
+	"设置收集器默认超时时间":                     "Set the default collector timeout",
+	"设置上下文收集器默认的超时时间（毫秒），超时的收集器会被跳过，0 表示使用默认值。": "Set the default timeout of context collectors in milliseconds. Collectors that time out are skipped. 0 means the default.",
+	"超过 %v 未完成，已跳过": "did not finish within %v, skipped",
+
+	// 重试
+	"请求失败，%v 后重试 (%d/%d): %v": "request failed, retrying in %v (%d/%d): %v",
+	"发送请求失败: %w":              "failed to send request: %w",
+	"API请求失败: 状态码 %d: %s":     "API request failed: status %d: %s",
+	"设置重试次数":                  "Set the number of retries",
+	"设置请求遇到限流、服务端错误或网络中断时的最多重试次数，0 表示不重试。重试间隔按指数退避增长，并遵循服务端返回的 Retry-After。": "Set the maximum number of retries when a request is rate limited, hits a server error or loses its connection. 0 disables retries. The delay grows exponentially and honors the Retry-After returned by the server.",
+	"重试次数必须是非负整数: %s":    "the number of retries must be a non-negative integer: %s",
+	"设置重试次数失败: %v":       "failed to set the number of retries: %v",
+	"已设置 RETRIES = %d\n": "RETRIES set to %d\n",
+
+	// API 错误提示
+	"%v\n提示: %s": "%v\nHint: %s",
+	"API 密钥无效或没有权限，请运行 ais config set key 设置正确的密钥":                            "the API key is invalid or lacks permission, run ais config set key to set a valid key",
+	"账户额度已用尽，请检查账户的余额和账单设置":                                                   "the account quota is exhausted, check the balance and billing settings of the account",
+	"请求过于频繁，请稍后再试，或运行 ais config set retries 增加重试次数":                          "too many requests, try again later or run ais config set retries to allow more retries",
+	"模型 %s 在 %s 上不可用，请运行 ais models --pick 选择可用的模型":                           "model %s is not available at %s, run ais models --pick to choose an available model",
+	"请求超出了模型的上下文长度，请使用 ais collector disable 或 ais collector budget 减少发送的上下文": "the request exceeds the context length of the model, use ais collector disable or ais collector budget to send less context",
+	"请求或回复被服务端的内容审核拦截，请换一种描述方式":                                               "the request or reply was blocked by the server's content filter, try rephrasing the request",
+	"服务端暂时不可用，请稍后再试":                                                          "the server is temporarily unavailable, try again later",
+	"无法连接到 %s，请检查网络，或运行 ais config set url 设置正确的地址":                           "could not connect to %s, check the network or run ais config set url to set the correct address",
+
+	// ais exec
+	"模型: %s  耗时: %v  请求次数: %d  令牌: 输入 %d / 输出 %d\n": "model: %s  latency: %v  attempts: %d  tokens: %d in / %d out\n",
+	"读取响应失败: %w": "failed to read response: %w",
+
+	// ais usage
+	"查看令牌用量和费用": "show token usage and cost",
+	`按日期和模型显示令牌用量和费用，默认显示本月。
+
+费用按记录时的价格计算，价格通过 ais config set price 设置，没有设置价格的模型费用按 0 计算。
+可以通过 ais config set budget-soft 和 ais config set budget-hard 设置月度预算。`: `Show token usage and cost by day and model, for the current month by default.
+
+Cost is computed with the prices in effect when each request was made. Set prices with ais config set price; models without a price count as 0.
+Set monthly budgets with ais config set budget-soft and ais config set budget-hard.`,
+	"要查看的月份，格式为 2006-01，默认为本月":                               "month to show, formatted as 2006-01, defaults to the current month",
+	"无效的月份: %s，格式应为 2006-01":                                 "invalid month: %s, expected the format 2006-01",
+	"%s 没有用量记录\n":                                            "No usage recorded for %s\n",
+	"注意: %s 有请求在记录时没有设置价格，费用按 0 计算\n":                        "Note: some %s requests had no price set when recorded and count as 0\n",
+	"软性预算: %s (已使用 %.0f%%)\n":                                "Soft budget: %s (%.0f%% used)\n",
+	"硬性预算: %s (已使用 %.0f%%)\n":                                "Hard budget: %s (%.0f%% used)\n",
+	"本月费用 %s 已达到硬性预算 %s，请运行 ais config set budget-hard 调整预算": "this month's cost %s has reached the hard budget %s, run ais config set budget-hard to adjust it",
+	"记录用量失败: %v":                                             "failed to record usage: %v",
+	"本月费用 %s 已达到硬性预算 %s，之后的请求将被拒绝":                           "this month's cost %s has reached the hard budget %s, further requests will be refused",
+	"本月费用 %s 已超过软性预算 %s":                                     "this month's cost %s exceeds the soft budget %s",
+	"读取用量记录失败: %v":                                           "failed to read usage records: %v",
+	"解析用量记录失败: %v":                                           "failed to parse usage records: %v",
+	"序列化用量记录失败: %v":                                          "failed to serialize usage records: %v",
+	"保存用量记录失败: %v":                                           "failed to save usage records: %v",
+
+	// ais config set price/budget
+	"设置模型价格": "set a model price",
+	"设置模型每百万 tokens 的输入和输出价格，用于统计费用。模型名称也可以是前缀，例如 gpt-4o 的价格同样适用于 gpt-4o-2024-08-06。": "Set the input and output price per million tokens of a model, used to compute cost. The model name may be a prefix, so a price for gpt-4o also applies to gpt-4o-2024-08-06.",
+	"价格必须是非负数: %s":                              "price must be a non-negative number: %s",
+	"设置模型价格失败: %v":                              "failed to set model price: %v",
+	"已设置 %s 的价格: 输入 %g / 输出 %g %s 每百万 tokens\n": "Price of %s: %g in / %g out %s per million tokens\n",
+	"设置月度软性预算":                                  "set the monthly soft budget",
+	"设置每月费用的软性预算，本月费用超过后每次请求都会发出警告，0 表示不限制。": "Set the monthly soft budget. Once this month's cost exceeds it, every request prints a warning. 0 means no limit.",
+	"设置月度硬性预算": "set the monthly hard budget",
+	"设置每月费用的硬性预算，本月费用达到后拒绝发送请求，0 表示不限制。": "Set the monthly hard budget. Once this month's cost reaches it, requests are refused. 0 means no limit.",
+	"预算必须是非负数: %s":              "budget must be a non-negative number: %s",
+	"设置预算失败: %v":                "failed to set budget: %v",
+	"已设置 BUDGET_SOFT = %g %s\n": "BUDGET_SOFT = %g %s\n",
+	"已设置 BUDGET_HARD = %g %s\n": "BUDGET_HARD = %g %s\n",
+
+	// ais usage 表头
+	"日期":        "DATE",
+	"模型":        "MODEL",
+	"服务":        "ENDPOINT",
+	"请求数":       "REQUESTS",
+	"输入 tokens": "INPUT TOKENS",
+	"输出 tokens": "OUTPUT TOKENS",
+	"费用":        "COST",
+	"合计":        "total",
+
+	// internal/cassette
+	"无效的 %s: %s，格式应为 record:目录 或 replay:目录": "invalid %s: %s, expected record:DIR or replay:DIR",
+	"创建录制目录失败: %v":                          "failed to create cassette directory: %v",
+	"保存录制失败: %v":                            "failed to save cassette: %v",
+	"没有与请求匹配的录制: %s":                        "no cassette matches the request: %s",
+	"解析录制失败: %s: %v":                        "failed to parse cassette: %s: %v",
+
+	// internal/cassette
+	"请求与录制时不同，请使用 %s=record:目录 重新录制": "the request differs from the recorded one, record it again with %s=record:DIR",
+
+	// ais dev
+	"开发辅助命令": "development helpers",
+	"用于开发和测试 AI-Shell 的辅助命令。": "Helper commands for developing and testing AI-Shell.",
+	"启动本地模拟 API 服务":           "start a local mock API server",
+	`启动兼容 OpenAI /v1/chat/completions 的本地模拟服务，按规则文件回复请求，
+可以注入延迟、错误状态码、格式错误的 JSON 和流式响应，用于离线测试各种错误处理。
+
+规则按顺序用正则表达式匹配用户的请求，第一条匹配的规则生效。没有指定规则文件时使用内置的示例规则，
+可以通过 --print-rules 输出示例规则作为编写规则文件的起点。规则文件在每次请求时重新读取。
+
+启动后运行 ais config set url http://127.0.0.1:8080/v1/chat/completions 即可使用。`: `Start a local mock server compatible with OpenAI /v1/chat/completions. It answers from a rules file
+and can inject latency, error status codes, malformed JSON and streaming responses to exercise error handling offline.
+
+Rules match the user's request with regular expressions in order, and the first match wins. Without a rules file
+the built-in example rules are used; print them with --print-rules as a starting point. The rules file is re-read on every request.
+
+Once started, run ais config set url http://127.0.0.1:8080/v1/chat/completions to use it.`,
+	"监听地址": "address to listen on",
+	"规则文件路径，为空时使用内置的示例规则":                      "path of the rules file, the built-in example rules are used when empty",
+	"输出内置的示例规则后退出":                             "print the built-in example rules and exit",
+	"监听 %s 失败: %v":                             "failed to listen on %s: %v",
+	"模拟服务已启动: http://%s/v1/chat/completions\n": "Mock server listening on http://%s/v1/chat/completions\n",
+	"模拟服务异常退出: %v":                             "mock server stopped unexpectedly: %v",
+	"解析规则失败: %v":                               "failed to parse rules: %v",
+	"规则 %d 的正则表达式无效: %v":                       "rule %d has an invalid regular expression: %v",
+	"规则 %d 的 response 不是有效的 JSON":              "rule %d: response is not valid JSON",
+	"读取规则文件失败: %v":                             "failed to read rules file: %v",
+	"匹配规则 %d":                                  "matched rule %d",
+
+	// ais config set 网络
+	"设置代理": "set the proxy",
+	"设置访问 API 使用的代理，支持 http、https 和 socks5，例如 http://proxy.example.com:3128。设置为空字符串时使用 HTTPS_PROXY 等环境变量。": "Set the proxy used to reach the API. http, https and socks5 are supported, e.g. http://proxy.example.com:3128. An empty string falls back to HTTPS_PROXY and related environment variables.",
+	"设置代理失败: %v":       "failed to set proxy: %v",
+	"已设置 PROXY = %s\n": "PROXY = %s\n",
+	"设置 CA 证书":         "set the CA certificate",
+	"设置在系统证书之外额外信任的 CA 证书文件（PEM 格式），用于访问使用企业 CA 签发证书的服务。设置为空字符串时只信任系统证书。": "Set a CA certificate file (PEM) to trust in addition to the system certificates, for services with certificates issued by a corporate CA. An empty string trusts only the system certificates.",
+	"设置 CA 证书失败: %v":     "failed to set CA certificate: %v",
+	"已设置 CA_FILE = %s\n": "CA_FILE = %s\n",
+	"设置客户端证书":            "set the client certificate",
+	"设置 mTLS 使用的客户端证书和私钥文件（PEM 格式）。都设置为空字符串时不使用客户端证书。": "Set the client certificate and private key files (PEM) used for mTLS. Set both to empty strings to stop using a client certificate.",
+	"客户端证书和私钥需要同时设置":                          "the client certificate and private key must be set together",
+	"设置客户端证书失败: %v":                           "failed to set client certificate: %v",
+	"已设置 CLIENT_CERT = %s, CLIENT_KEY = %s\n": "CLIENT_CERT = %s, CLIENT_KEY = %s\n",
+	"设置是否跳过证书校验":                              "set whether to skip certificate verification",
+	"设置是否跳过服务端证书校验。跳过校验后连接可能被窃听或篡改，请只在调试时使用。": "Set whether to skip verification of the server certificate. Without verification the connection can be intercepted or tampered with, so only use it for debugging.",
+	"设置证书校验失败: %v":                    "failed to set certificate verification: %v",
+	"已设置 INSECURE_SKIP_VERIFY = %v\n": "INSECURE_SKIP_VERIFY = %v\n",
+	"已跳过服务端证书校验，连接可能被窃听或篡改，请只在调试时使用": "server certificate verification is disabled, the connection can be intercepted or tampered with, only use this for debugging",
+
+	// internal/openai 网络
+	"无效的代理地址: %s":                            "invalid proxy URL: %s",
+	"不支持的代理协议: %s (可用: http, https, socks5)": "unsupported proxy scheme: %s (available: http, https, socks5)",
+	"读取 CA 证书失败: %v":                         "failed to read CA certificate: %v",
+	"CA 证书文件中没有有效的 PEM 证书: %s":               "no valid PEM certificate in CA file: %s",
+	"加载客户端证书失败: %v":                          "failed to load client certificate: %v",
+
+	// API 错误提示
+	"服务端证书不受信任，如果服务使用企业 CA 签发的证书，请运行 ais config set ca-file 设置 CA 证书": "the server certificate is not trusted, if the service uses a certificate issued by a corporate CA, run ais config set ca-file to set the CA certificate",
+	"服务端要求客户端证书，请运行 ais config set client-cert 设置客户端证书和私钥":            "the server requires a client certificate, run ais config set client-cert to set the client certificate and private key",
+	"无法连接到代理，请检查 ais config set proxy 或 HTTPS_PROXY 环境变量设置的代理地址":      "cannot connect to the proxy, check the proxy set with ais config set proxy or the HTTPS_PROXY environment variable",
+
+	// ais config set 请求
+	"设置请求头": "set a request header",
+	"设置请求 API 时附加的 HTTP 头，例如 OpenAI-Organization、HTTP-Referer，可以覆盖默认的 Authorization。值中可以使用 ${NAME} 引用环境变量，值为空字符串时删除该请求头。": "Set an HTTP header sent with API requests, such as OpenAI-Organization or HTTP-Referer. It may override the default Authorization header. Values may reference environment variables as ${NAME}. An empty value removes the header.",
+	"设置请求头失败: %v":      "failed to set header: %v",
+	"已删除请求头 %s\n":      "Removed header %s\n",
+	"已设置请求头 %s = %s\n": "Header %s = %s\n",
+	"设置请求体字段":          "set a request body field",
+	"设置合并到请求体中的字段，例如 top_p、seed、reasoning_effort。值按 JSON 解析，不是有效的 JSON 时作为字符串，字符串中可以使用 ${NAME} 引用环境变量。值为空字符串时删除该字段。": "Set a field merged into the request body, such as top_p, seed or reasoning_effort. The value is parsed as JSON and used as a string when it is not valid JSON; strings may reference environment variables as ${NAME}. An empty value removes the field.",
+	"设置请求体字段失败: %v":             "failed to set request body field: %v",
+	"已删除请求体字段 %s\n":             "Removed request body field %s\n",
+	"已设置请求体字段 %s = %s\n":        "Request body field %s = %s\n",
+	"环境变量 %s 未设置":               "environment variable %s is not set",
+	"extra_body 不能覆盖 %s 字段，已忽略": "extra_body cannot override the %s field, ignored",
+
+	// ais models
+	"列出可用的模型": "list available models",
+	`列出当前 API 服务提供的模型，当前使用的模型以 * 标注。
+
+兼容 OpenAI 的服务通过 /v1/models 获取，Ollama 通过 /api/tags 获取。
+结果缓存在配置目录的 models_cache.json 中 24 小时，使用 --refresh 重新获取。
+使用 --pick 从列表中选择模型并写入配置。`: `List the models offered by the current API service. The model in use is marked with *.
+
+OpenAI compatible services are queried through /v1/models, Ollama through /api/tags.
+Results are cached in models_cache.json in the config directory for 24 hours; use --refresh to fetch them again.
+Use --pick to choose a model from the list and save it to the config.`,
+	"忽略缓存，重新获取模型列表":       "ignore the cache and fetch the model list again",
+	"从列表中选择模型并写入配置":       "choose a model from the list and save it to the config",
+	"服务没有返回任何模型":          "the service returned no models",
+	"上下文 %d tokens":       "context %d tokens",
+	"注意: 当前模型 %s 不在列表中\n": "Note: the current model %s is not in the list\n",
+	"请选择模型 (0: 退出): ":     "Choose a model (0: quit): ",
+	"注意: 模型 %s 不在 %s 提供的模型列表中，请运行 ais models 查看可用的模型\n": "Note: model %s is not in the model list of %s, run ais models to see the available models\n",
+	"无效的 API 地址: %s": "invalid API URL: %s",
+	"无法从 %s 推断模型列表的地址，API 地址应以 /chat/completions 结尾": "cannot derive the model list URL from %s, the API URL should end with /chat/completions",
+	"解析模型列表失败: %v": "failed to parse model list: %v",
+
+	// ais models
+	"%s 不支持获取模型列表 (状态码 %d)": "%s does not support listing models (status %d)",
+
+	// 备用模型
+	"添加备用模型失败: %v":                   "failed to add fallback model: %v",
+	"已添加备用模型 %d: %s\n":               "Added fallback model %d: %s\n",
+	"还没有备用模型，使用 ais fallback add 添加": "No fallback models yet, add one with ais fallback add",
+	"主模型: %s @ %s\n":                 "Primary model: %s @ %s\n",
+	"(主配置的地址)":                       "(primary URL)",
+	"  (独立的密钥)":                      "  (own API key)",
+	"无效的序号: %s":                      "invalid index: %s",
+	"删除备用模型失败: %v":                   "failed to remove fallback model: %v",
+	"已删除备用模型 %d: %s\n":               "Removed fallback model %d: %s\n",
+	"备用模型管理命令":                       "Manage fallback models",
+	`管理备用模型。主模型因限流、服务端错误或网络故障不可用时，按顺序改用备用模型，
+回答请求的备用模型显示在回复下方，并记录在 ais usage 中。
+
+备用模型可以使用不同的服务和密钥。未指定地址时使用主配置的地址、密钥、请求头和附加字段；
+使用其他地址时不会发送主配置的密钥和请求头，服务需要认证时通过 --key 指定密钥。
+本地的 Ollama 模型适合作为最后一个备用模型，使 ais 在离线时仍然可用。`: `Manage fallback models. When the primary model is unavailable because of rate limiting, server errors or network failures, the fallback models are tried in order.
+The fallback model that answered is shown below the reply and recorded in ais usage.
+
+Fallback models may use a different service and API key. Without a URL, the primary URL, key, headers and extra body fields are used;
+with a different URL the primary key and headers are never sent, pass --key if the service requires authentication.
+A local Ollama model works well as the last fallback, keeping ais usable offline.`,
+	"添加备用模型": "Add a fallback model",
+	`将备用模型添加到列表末尾，例如:
+  ais fallback add gpt-4o --url https://api.example.com/v1/chat/completions --key '${EXAMPLE_API_KEY}'
+  ais fallback add llama3.2 --url http://localhost:11434/v1/chat/completions`: `Append a fallback model to the list, for example:
+  ais fallback add gpt-4o --url https://api.example.com/v1/chat/completions --key '${EXAMPLE_API_KEY}'
+  ais fallback add llama3.2 --url http://localhost:11434/v1/chat/completions`,
+	"列出备用模型":                           "List fallback models",
+	"按尝试顺序列出备用模型。":                     "List fallback models in the order they are tried.",
+	"删除备用模型":                           "Remove a fallback model",
+	"删除 ais fallback list 中指定序号的备用模型。": "Remove the fallback model with the given index from ais fallback list.",
+	"备用模型的 API 地址，为空时使用主配置的地址":         "API URL of the fallback model, defaults to the primary URL",
+	"备用模型的 API 密钥，可以使用 ${NAME} 引用环境变量，未指定 --url 时默认使用主配置的密钥": "API key of the fallback model, may reference environment variables with ${NAME}, defaults to the primary key when --url is not set",
+	"(由备用模型 %s @ %s 回答)\n":    "(answered by fallback model %s @ %s)\n",
+	"备用模型的名称不能为空":             "fallback model name must not be empty",
+	"备用模型不存在: %d":             "fallback model does not exist: %d",
+	"模型 %s 不可用，改用备用模型 %s: %v": "model %s is unavailable, falling back to %s: %v",
+
+	// 别名
+	"记录别名 %s 的命令失败: %v": "failed to record the command of alias %s: %v",
+}
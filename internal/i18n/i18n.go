@@ -0,0 +1,100 @@
+// Package i18n 提供界面文本的本地化。
+//
+// 代码中的中文原文即为消息的键，其他语言的译文在对应的目录表中维护。
+// 没有译文的消息会回退为中文原文，这样新增文本时不会因为漏译而出错。
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	Chinese = "zh"
+	English = "en"
+)
+
+// catalogs 保存各语言的译文，中文原文不需要目录表
+var catalogs = map[string]map[string]string{
+	English: english,
+}
+
+// displayNames 为各语言在提示中使用的名称
+var displayNames = map[string]string{
+	Chinese: "中文",
+	English: "English",
+}
+
+var current = Detect()
+
+// Detect 根据 LC_ALL、LC_MESSAGES、LANG 环境变量检测界面语言，无法识别时使用中文
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if lang := Normalize(os.Getenv(name)); lang != "" {
+			return lang
+		}
+	}
+	return Chinese
+}
+
+// Normalize 将 zh_CN.UTF-8、en-US 之类的语言环境名称转换为支持的语言代码，
+// 不支持的语言返回空字符串
+func Normalize(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if _, ok := displayNames[locale]; ok {
+		return locale
+	}
+	return ""
+}
+
+// Supported 返回所有支持的语言代码
+func Supported() []string {
+	return []string{Chinese, English}
+}
+
+// SetLanguage 设置界面语言，传入空字符串或 auto 时根据环境变量自动检测
+func SetLanguage(lang string) error {
+	if lang == "" || lang == "auto" {
+		current = Detect()
+		return nil
+	}
+
+	normalized := Normalize(lang)
+	if normalized == "" {
+		return Errorf("不支持的语言: %s (可用: auto, %s)", lang, strings.Join(Supported(), ", "))
+	}
+	current = normalized
+	return nil
+}
+
+// Language 返回当前界面语言代码
+func Language() string {
+	return current
+}
+
+// DisplayName 返回当前语言在提示中使用的名称，如“中文”、“English”
+func DisplayName() string {
+	return displayNames[current]
+}
+
+// T 返回消息在当前语言下的译文
+func T(message string) string {
+	if translated, ok := catalogs[current][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf 与 fmt.Sprintf 相同，但格式字符串会先被翻译
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf 与 fmt.Errorf 相同，但格式字符串会先被翻译
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
@@ -0,0 +1,286 @@
+// Package mockserver 提供兼容 OpenAI /v1/chat/completions 的本地模拟服务，用于离线开发和测试。
+//
+// 模拟服务按规则文件回复请求：规则按顺序用正则表达式匹配用户请求，即用户消息的最后一行，
+// 第一条匹配的规则决定回复的内容，也可以注入延迟、错误状态码、格式错误的 JSON 和流式响应。
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"AI-Shell/internal/i18n"
+)
+
+// Rule 为一条回复规则
+type Rule struct {
+	Match      string          `json:"match"`                 // 匹配用户请求的正则表达式，为空时匹配所有请求
+	Response   json.RawMessage `json:"response,omitempty"`    // 作为回复内容的 JSON，通常为 {"command": [...], "msg": "...", "code": 0}
+	Content    string          `json:"content,omitempty"`     // 原样作为回复内容的文本，优先于 response
+	Status     int             `json:"status,omitempty"`      // 非 200 时返回 OpenAI 格式的错误
+	Error      *ErrorBody      `json:"error,omitempty"`       // 错误响应的内容，未设置时根据状态码生成
+	RetryAfter int             `json:"retry_after,omitempty"` // 错误响应的 Retry-After 响应头（秒）
+	Latency    int             `json:"latency_ms,omitempty"`  // 回复前等待的时间（毫秒）
+	Malformed  bool            `json:"malformed,omitempty"`   // 返回被截断的 JSON
+	Stream     bool            `json:"stream,omitempty"`      // 以 SSE 分块返回回复内容
+	Times      int             `json:"times,omitempty"`       // 只对前几次匹配的请求生效，之后继续匹配后面的规则，0 表示不限制
+
+	pattern *regexp.Regexp
+}
+
+// ErrorBody 为 OpenAI 格式的错误内容
+type ErrorBody struct {
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// Rules 为规则文件的内容
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ExampleRules 为没有指定规则文件时使用的规则，也可以作为编写规则文件的示例
+const ExampleRules = `{
+  "rules": [
+    {"match": "(?i)rate.?limit", "status": 429, "retry_after": 1, "times": 2},
+    {"match": "(?i)quota", "status": 429, "error": {"message": "You exceeded your current quota", "type": "insufficient_quota", "code": "insufficient_quota"}},
+    {"match": "(?i)unauthorized", "status": 401},
+    {"match": "(?i)server.?error", "status": 500},
+    {"match": "(?i)malformed", "malformed": true},
+    {"match": "(?i)stream", "stream": true, "response": {"command": ["ls -la"], "msg": "stream", "code": 0}},
+    {"match": "(?i)slow", "latency_ms": 3000, "response": {"command": ["sleep 1"], "msg": "slow", "code": 0}},
+    {"match": "(?i)markdown", "content": "` + "```json\\n{\\\"command\\\": [\\\"pwd\\\"], \\\"msg\\\": \\\"markdown\\\", \\\"code\\\": 0}\\n```" + `"},
+    {"match": "(?i)refuse", "response": {"command": [], "msg": "mock: cannot translate", "code": 1}},
+    {"response": {"command": ["ls", "ls -la", "find . -maxdepth 1"], "msg": "mock", "code": 0, "confidence": [0.9, 0.6, 0.3]}}
+  ]
+}
+`
+
+// mockModels 为 /v1/models 返回的模型列表，模拟服务接受任意模型名称
+var mockModels = []map[string]any{
+	{"id": "mock-large", "object": "model", "owned_by": "ais", "context_length": 128000},
+	{"id": "mock-small", "object": "model", "owned_by": "ais", "context_length": 8192},
+}
+
+// ParseRules 解析并校验规则
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, i18n.Errorf("解析规则失败: %v", err)
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		pattern, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, i18n.Errorf("规则 %d 的正则表达式无效: %v", i+1, err)
+		}
+		rule.pattern = pattern
+		if rule.Response != nil && !json.Valid(rule.Response) {
+			return nil, i18n.Errorf("规则 %d 的 response 不是有效的 JSON", i+1)
+		}
+	}
+	return &rules, nil
+}
+
+// Server 为模拟服务，实现 http.Handler
+type Server struct {
+	path   string // 规则文件路径，为空时使用 ExampleRules
+	mu     sync.Mutex
+	counts map[int]int // 每条规则已生效的次数，键为规则序号
+}
+
+// New 创建模拟服务，path 为空时使用 ExampleRules。规则文件在每次请求时重新读取，修改后无需重启
+func New(path string) (*Server, error) {
+	server := &Server{path: path, counts: make(map[int]int)}
+	if _, err := server.loadRules(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+func (s *Server) loadRules() (*Rules, error) {
+	if s.path == "" {
+		return ParseRules([]byte(ExampleRules))
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, i18n.Errorf("读取规则文件失败: %v", err)
+	}
+	return ParseRules(data)
+}
+
+// request 为模拟服务关心的请求字段
+type request struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/models") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": mockModels})
+		return
+	}
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		writeError(w, http.StatusNotFound, &ErrorBody{Message: "unknown path " + r.URL.Path, Type: "invalid_request_error"}, 0)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	var req request
+	if err == nil {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, &ErrorBody{Message: err.Error(), Type: "invalid_request_error"}, 0)
+		return
+	}
+
+	rules, err := s.loadRules()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, &ErrorBody{Message: err.Error(), Type: "server_error"}, 0)
+		return
+	}
+
+	// 用户消息由系统信息和用户请求组成，用户请求在最后一行
+	var prompt, query string
+	for _, message := range req.Messages {
+		prompt += message.Content
+		if message.Role == "user" {
+			query = message.Content[strings.LastIndex(message.Content, "\n")+1:]
+		}
+	}
+	index, rule := s.match(rules, query)
+	if rule == nil {
+		writeError(w, http.StatusInternalServerError, &ErrorBody{Message: "no rule matches the request", Type: "server_error"}, 0)
+		return
+	}
+	slog.Info(i18n.Sprintf("匹配规则 %d", index+1), "query", query, "match", rule.Match, "model", req.Model)
+
+	if rule.Latency > 0 {
+		select {
+		case <-time.After(time.Duration(rule.Latency) * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if rule.Status != 0 && rule.Status != http.StatusOK {
+		errBody := rule.Error
+		if errBody == nil {
+			errBody = defaultError(rule.Status)
+		}
+		writeError(w, rule.Status, errBody, rule.RetryAfter)
+		return
+	}
+
+	content := rule.Content
+	if content == "" && rule.Response != nil {
+		content = string(rule.Response)
+	}
+	if rule.Stream {
+		writeStream(w, req.Model, content)
+		return
+	}
+
+	data, _ := json.Marshal(map[string]any{
+		"id":      "chatcmpl-mock",
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   req.Model,
+		"choices": []map[string]any{{
+			"index":         0,
+			"message":       map[string]string{"role": "assistant", "content": content},
+			"finish_reason": "stop",
+		}},
+		"usage": map[string]int{
+			"prompt_tokens":     estimateTokens(prompt),
+			"completion_tokens": estimateTokens(content),
+			"total_tokens":      estimateTokens(prompt) + estimateTokens(content),
+		},
+	})
+	if rule.Malformed {
+		data = data[:len(data)/2]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// match 返回第一条匹配且未用完次数的规则，并记录生效次数
+func (s *Server) match(rules *Rules, query string) (int, *Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if !rule.pattern.MatchString(query) {
+			continue
+		}
+		if rule.Times > 0 && s.counts[i] >= rule.Times {
+			continue
+		}
+		s.counts[i]++
+		return i, rule
+	}
+	return -1, nil
+}
+
+// defaultError 返回与状态码对应的 OpenAI 格式错误
+func defaultError(status int) *ErrorBody {
+	switch status {
+	case http.StatusUnauthorized:
+		return &ErrorBody{Message: "Incorrect API key provided", Type: "invalid_request_error", Code: "invalid_api_key"}
+	case http.StatusNotFound:
+		return &ErrorBody{Message: "The model does not exist", Type: "invalid_request_error", Code: "model_not_found"}
+	case http.StatusTooManyRequests:
+		return &ErrorBody{Message: "Rate limit reached", Type: "requests", Code: "rate_limit_exceeded"}
+	}
+	return &ErrorBody{Message: http.StatusText(status), Type: "server_error"}
+}
+
+func writeError(w http.ResponseWriter, status int, body *ErrorBody, retryAfter int) {
+	w.Header().Set("Content-Type", "application/json")
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]*ErrorBody{"error": body})
+}
+
+// writeStream 以 SSE 格式分块返回回复内容，每块几个字符
+func writeStream(w http.ResponseWriter, model, content string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher, _ := w.(http.Flusher)
+	runes := []rune(content)
+	for start := 0; start < len(runes); start += 8 {
+		chunk := string(runes[start:min(start+8, len(runes))])
+		data, _ := json.Marshal(map[string]any{
+			"id":      "chatcmpl-mock",
+			"object":  "chat.completion.chunk",
+			"model":   model,
+			"choices": []map[string]any{{"index": 0, "delta": map[string]string{"content": chunk}}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// estimateTokens 粗略估算 token 数，约 4 个字节一个 token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
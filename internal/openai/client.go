@@ -2,12 +2,17 @@ package openai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"AI-Shell/internal/cassette"
 	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
 )
 
 // Message 表示对话消息
@@ -23,6 +28,33 @@ type Request struct {
 	MaxTokens   int       `json:"max_tokens"`
 	Temperature float64   `json:"temperature"`
 	Stream      bool      `json:"stream"`
+
+	// Extra 合并到请求体中的额外字段，如 top_p、seed、reasoning_effort，
+	// 可以覆盖 max_tokens 等参数，但不能覆盖 model、messages 和 stream
+	Extra map[string]any `json:"-"`
+}
+
+// protectedFields 为 Extra 不能覆盖的字段
+var protectedFields = map[string]bool{"model": true, "messages": true, "stream": true}
+
+// MarshalJSON 将 Extra 中的字段合并到请求体中
+func (r Request) MarshalJSON() ([]byte, error) {
+	type plain Request
+	data, err := json.Marshal(plain(r))
+	if err != nil || len(r.Extra) == 0 {
+		return data, err
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	for key, value := range r.Extra {
+		if !protectedFields[key] {
+			body[key] = value
+		}
+	}
+	return json.Marshal(body)
 }
 
 // Choice 表示 API 响应中的选择
@@ -30,134 +62,221 @@ type Choice struct {
 	Message struct {
 		Content string `json:"content"`
 	} `json:"message"`
+	FinishReason string `json:"finish_reason,omitempty"`
+}
+
+// Usage 表示请求消耗的令牌数
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // Response 表示从 OpenAI API 接收到的响应
 type Response struct {
+	ID      string   `json:"id,omitempty"`
+	Model   string   `json:"model,omitempty"`
 	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
 }
 
-// RequestResponse 包含请求和响应数据
-type RequestResponse struct {
-	Request  *Request  `json:"request"`
-	Response *Response `json:"response"`
+// Options 表示一次补全请求的参数
+type Options struct {
+	SystemPrompt string
+	UserPrompt   string
+	Model        string // 为空时使用配置中的模型
+}
+
+// Result 表示一次补全请求的结果，包含请求、响应和用于观测的数据
+type Result struct {
+	Request  *Request
+	Response *Response
+	Raw      []byte        // 原始响应体
+	Header   http.Header   // 最后一次响应的响应头
+	Model    string        // 实际回答的模型，服务端未返回时为请求的模型
+	Usage    Usage         // 服务端未返回时为零值
+	Latency  time.Duration // 从发送第一次请求到收到响应的耗时，包含重试
+	Attempts int           // 发送请求的次数，包含向备用模型发送的请求
+	URL      string        // 最后一次请求的地址
+	Fallback int           // 回答请求的备用模型序号，从 1 开始，0 表示主模型
 }
 
 // Client OpenAI API 客户端
 type Client struct {
 	config *config.Config
 	client *http.Client
+	retry  retryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error // 重试前的等待，便于测试时替换
+}
+
+// NewClient 创建新的 OpenAI 客户端，按配置设置代理、CA 证书和客户端证书。
+// 设置了 AIS_CASSETTE 环境变量时，请求会被录制到目录中，或从目录中回放，见 cassette 包
+func NewClient(cfg *config.Config) (*Client, error) {
+	base, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := cassette.FromEnv(base)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithTransport(cfg, transport), nil
 }
 
-// NewClient 创建新的 OpenAI 客户端
-func NewClient(cfg *config.Config) *Client {
+// NewClientWithTransport 使用指定的 http.RoundTripper 创建客户端，便于测试时替换
+func NewClientWithTransport(cfg *config.Config, transport http.RoundTripper) *Client {
+	maxRetries, baseDelay, maxDelay := cfg.RetrySettings()
 	return &Client{
 		config: cfg,
 		client: &http.Client{
-			Timeout: time.Second * 30,
+			Transport: transport,
+			Timeout:   time.Second * 30,
 		},
+		retry: retryPolicy{maxRetries: maxRetries, baseDelay: baseDelay, maxDelay: maxDelay},
+		sleep: sleepContext,
 	}
 }
 
-// SendRequest 发送请求到 OpenAI API
-func (c *Client) SendRequest(systemPrompt, userPrompt string) (*Response, error) {
-	messages := []Message{
-		{Role: "system", Content: systemPrompt},
-		{Role: "user", Content: userPrompt},
-	}
-
-	reqBody := Request{
-		Model:       c.config.Model,
-		Messages:    messages,
-		MaxTokens:   c.config.MaxTokens,
-		Temperature: c.config.Temperature,
-		Stream:      false,
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("序列化请求失败: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", c.config.URL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
+// Complete 发送补全请求，遇到限流、服务端错误和网络中断等临时性错误时按重试策略重试。
+// 重试后仍然失败，或配置了备用模型时遇到限流，按顺序改用备用模型，实际回答的模型和地址记录在 Result 中。
+// 请求失败时返回的 Result 仍包含请求、尝试次数和耗时。
+func (c *Client) Complete(ctx context.Context, opts Options) (*Result, error) {
+	target := c.config
+	if opts.Model != "" {
+		target = c.config.Resolve(config.Fallback{Model: opts.Model})
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %v", err)
+	result := &Result{}
+	start := time.Now()
+	defer func() { result.Latency = time.Since(start) }()
+	for i := 0; ; i++ {
+		result.URL, result.Fallback = target.URL, i
+		extra, header := requestExtras(target, i == 0)
+		err := c.complete(ctx, target, opts, extra, header, result, i < len(c.config.Fallbacks))
+		if err == nil || !canFailover(err) || ctx.Err() != nil || i == len(c.config.Fallbacks) {
+			return result, err
+		}
+		// 备用模型的密钥在用到时才展开，避免未使用的备用模型引用的环境变量未设置时发出警告
+		next := c.config.Resolve(c.config.Fallbacks[i])
+		slog.Warn(i18n.Sprintf("模型 %s 不可用，改用备用模型 %s: %v", target.Model, next.Model, err))
+		target = next
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var errResp map[string]any
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return nil, fmt.Errorf("API请求失败: 状态码 %d", resp.StatusCode)
+// requestExtras 展开 target 中请求头和附加字段引用的环境变量，warn 为 true 时提示被忽略的附加字段。
+// 使用其他地址的备用模型没有请求头和附加字段，见 config.Config.Resolve
+func requestExtras(target *config.Config, warn bool) (map[string]any, http.Header) {
+	var extra map[string]any
+	if len(target.ExtraBody) > 0 {
+		extra = config.ExpandEnvValue(target.ExtraBody).(map[string]any)
+		for key := range extra {
+			if warn && protectedFields[key] {
+				slog.Warn(i18n.Sprintf("extra_body 不能覆盖 %s 字段，已忽略", key))
+			}
 		}
-		return nil, fmt.Errorf("API请求失败: %v", errResp)
 	}
-
-	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %v", err)
+	header := make(http.Header)
+	for name, value := range target.Headers {
+		header.Set(name, config.ExpandEnv(value))
 	}
-
-	return &response, nil
+	return extra, header
 }
 
-// SendRequestWithData 发送请求到 OpenAI API 并返回请求和响应数据
-func (c *Client) SendRequestWithData(systemPrompt, userPrompt string) (*RequestResponse, error) {
-	messages := []Message{
-		{Role: "system", Content: systemPrompt},
-		{Role: "user", Content: userPrompt},
-	}
-
-	reqBody := Request{
-		Model:       c.config.Model,
-		Messages:    messages,
+// complete 使用 target 中的地址、密钥和模型发送请求并按重试策略重试。
+// hasFallback 为 true 时遇到限流直接返回，由调用方改用备用模型，不再等待
+func (c *Client) complete(ctx context.Context, target *config.Config, opts Options, extra map[string]any, header http.Header, result *Result, hasFallback bool) error {
+	result.Request = &Request{
+		Model: target.Model,
+		Messages: []Message{
+			{Role: "system", Content: opts.SystemPrompt},
+			{Role: "user", Content: opts.UserPrompt},
+		},
 		MaxTokens:   c.config.MaxTokens,
 		Temperature: c.config.Temperature,
 		Stream:      false,
+		Extra:       extra,
 	}
+	result.Response = nil
 
-	jsonData, err := json.Marshal(reqBody)
+	jsonData, err := json.Marshal(result.Request)
 	if err != nil {
-		return nil, fmt.Errorf("序列化请求失败: %v", err)
+		return i18n.Errorf("序列化请求失败: %v", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		result.Attempts++
+		err := c.sendOnce(ctx, target, jsonData, header, result)
+		if err == nil {
+			result.Model = target.Model
+			if result.Response.Model != "" {
+				result.Model = result.Response.Model
+			}
+			if result.Response.Usage != nil {
+				result.Usage = *result.Response.Usage
+			}
+			return nil
+		}
+
+		var rateLimitErr *RateLimitError
+		if hasFallback && errors.As(err, &rateLimitErr) {
+			return err
+		}
+		delay, retry := c.retry.retryDelay(err, attempt)
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+		slog.Warn(i18n.Sprintf("请求失败，%v 后重试 (%d/%d): %v", delay.Round(time.Millisecond), attempt+1, c.retry.maxRetries, err))
+		if err := c.sleep(ctx, delay); err != nil {
+			return err
+		}
 	}
+}
 
-	req, err := http.NewRequest("POST", c.config.URL, bytes.NewBuffer(jsonData))
+// sendOnce 附加配置中的请求头 header 向 target 发送一次请求，将响应头、原始响应体和解析后的响应记录到 result，非 200 响应返回 *APIError
+func (c *Client) sendOnce(ctx context.Context, target *config.Config, jsonData []byte, header http.Header, result *Result) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", target.URL, bytes.NewReader(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
+		return i18n.Errorf("创建请求失败: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	if target.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+target.APIKey)
+	}
+	// 配置中的请求头可以覆盖上面的默认请求头
+	for name, values := range header {
+		req.Header[name] = values
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %v", err)
+		return i18n.Errorf("发送请求失败: %w", err)
 	}
 	defer resp.Body.Close()
+	result.Header = resp.Header
 
 	if resp.StatusCode != http.StatusOK {
-		var errResp map[string]any
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return nil, fmt.Errorf("API请求失败: 状态码 %d", resp.StatusCode)
-		}
-		return nil, fmt.Errorf("API请求失败: %v", errResp)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		result.Raw = body
+		return classify(parseAPIError(resp.StatusCode, resp.Header, body))
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return i18n.Errorf("读取响应失败: %w", err)
+	}
+	result.Raw = body
+
 	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %v", err)
+	if err := json.Unmarshal(body, &response); err != nil {
+		return i18n.Errorf("解析响应失败: %v", err)
+	}
+
+	// Azure 等服务在回复被拦截时仍返回 200，只在 finish_reason 中注明
+	if len(response.Choices) > 0 && response.Choices[0].FinishReason == "content_filter" && response.Choices[0].Message.Content == "" {
+		return &ContentFilterError{&APIError{StatusCode: resp.StatusCode, Code: "content_filter", Message: "finish_reason: content_filter"}}
 	}
 
-	return &RequestResponse{
-		Request:  &reqBody,
-		Response: &response,
-	}, nil
+	result.Response = &response
+	return nil
 }
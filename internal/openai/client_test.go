@@ -0,0 +1,207 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"AI-Shell/internal/config"
+)
+
+const okBody = `{"id":"chatcmpl-test","model":"gpt-test","choices":[{"index":0,"message":{"role":"assistant","content":"{}"},"finish_reason":"stop"}]}`
+
+// reply 为测试服务的一次响应
+type reply struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// newTestClient 启动按顺序返回 replies 的测试服务，最后一个响应会一直重复。
+// 返回的客户端最多重试 2 次，不真正等待，等待时间记录在 delays 中
+func newTestClient(t *testing.T, replies ...reply) (client *Client, requests *atomic.Int32, delays *[]time.Duration) {
+	t.Helper()
+	requests = new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1)) - 1
+		rep := replies[min(n, len(replies)-1)]
+		for name, value := range rep.header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rep.status)
+		w.Write([]byte(rep.body))
+	}))
+	t.Cleanup(server.Close)
+
+	maxRetries := 2
+	cfg := &config.Config{
+		URL:    server.URL + "/v1/chat/completions",
+		APIKey: "sk-test",
+		Model:  "gpt-test",
+		Retry:  config.RetryConfig{MaxRetries: &maxRetries, BaseDelay: 10, MaxDelay: 40},
+	}
+	client = NewClientWithTransport(cfg, http.DefaultTransport)
+	delays = new([]time.Duration)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return client, requests, delays
+}
+
+func TestCompleteRetriesAfterServerDelay(t *testing.T) {
+	client, requests, delays := newTestClient(t,
+		reply{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "3"}, body: `{"error":{"message":"Rate limit reached","code":"rate_limit_exceeded"}}`},
+		reply{status: http.StatusOK, body: okBody},
+	)
+
+	result, err := client.Complete(context.Background(), Options{UserPrompt: "ls"})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if result.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", result.Attempts)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 3*time.Second {
+		t.Errorf("delays = %v, want [3s]", *delays)
+	}
+}
+
+func TestCompleteGivesUpAfterMaxRetries(t *testing.T) {
+	client, requests, delays := newTestClient(t,
+		reply{status: http.StatusServiceUnavailable, body: `{"error":{"message":"overloaded","type":"server_error"}}`},
+	)
+
+	result, err := client.Complete(context.Background(), Options{UserPrompt: "ls"})
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("Complete() error = %v, want *ServerError", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+	if result.Attempts != 3 || len(*delays) != 2 {
+		t.Errorf("Attempts = %d, delays = %v, want 3 attempts and 2 delays", result.Attempts, *delays)
+	}
+}
+
+func TestCompleteDoesNotRetryInsufficientQuota(t *testing.T) {
+	client, requests, delays := newTestClient(t,
+		reply{status: http.StatusTooManyRequests, body: `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`},
+	)
+
+	_, err := client.Complete(context.Background(), Options{UserPrompt: "ls"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Complete() error = %v, want *QuotaError", err)
+	}
+	if got := requests.Load(); got != 1 || len(*delays) != 0 {
+		t.Errorf("requests = %d, delays = %v, want 1 request and no delay", got, *delays)
+	}
+}
+
+func TestCompleteReadsRateLimitResetOnlyFor429(t *testing.T) {
+	resetHeader := map[string]string{"X-Ratelimit-Reset-Requests": "2s", "X-Ratelimit-Reset-Tokens": "6m0s"}
+
+	t.Run("429", func(t *testing.T) {
+		client, _, delays := newTestClient(t,
+			reply{status: http.StatusTooManyRequests, header: map[string]string{"X-Ratelimit-Reset-Requests": "2s", "X-Ratelimit-Reset-Tokens": "500ms"}},
+			reply{status: http.StatusOK, body: okBody},
+		)
+		if _, err := client.Complete(context.Background(), Options{UserPrompt: "ls"}); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+			t.Errorf("delays = %v, want [2s]", *delays)
+		}
+	})
+
+	t.Run("503", func(t *testing.T) {
+		client, _, delays := newTestClient(t,
+			reply{status: http.StatusServiceUnavailable, header: resetHeader},
+			reply{status: http.StatusOK, body: okBody},
+		)
+		if _, err := client.Complete(context.Background(), Options{UserPrompt: "ls"}); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		// 非 429 的响应忽略这两个响应头，使用指数退避
+		if len(*delays) != 1 || (*delays)[0] > 10*time.Millisecond {
+			t.Errorf("delays = %v, want one backoff of at most 10ms", *delays)
+		}
+	})
+
+	t.Run("200", func(t *testing.T) {
+		if got := parseRetryAfter(http.StatusOK, headerOf(resetHeader), time.Now()); got != 0 {
+			t.Errorf("parseRetryAfter(200) = %v, want 0", got)
+		}
+	})
+}
+
+func TestCompleteStopsWhenCancelledDuringBackoff(t *testing.T) {
+	client, requests, _ := newTestClient(t,
+		reply{status: http.StatusServiceUnavailable},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	result, err := client.Complete(ctx, Options{UserPrompt: "ls"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Complete() error = %v, want context.Canceled", err)
+	}
+	if got := requests.Load(); got != 1 || result.Attempts != 1 {
+		t.Errorf("requests = %d, Attempts = %d, want 1", got, result.Attempts)
+	}
+}
+
+func headerOf(values map[string]string) http.Header {
+	header := make(http.Header)
+	for name, value := range values {
+		header.Set(name, value)
+	}
+	return header
+}
+
+func TestCompleteFallbackDoesNotLeakCredentials(t *testing.T) {
+	client, primaryRequests, _ := newTestClient(t,
+		reply{status: http.StatusTooManyRequests, body: `{"error":{"message":"Rate limit reached"}}`},
+	)
+	client.config.Headers = map[string]string{"X-Tenant-Token": "tenant-secret"}
+
+	var header http.Header
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(okBody))
+	}))
+	defer fallback.Close()
+	client.config.Fallbacks = []config.Fallback{{URL: fallback.URL + "/v1/chat/completions", Model: "llama3.2"}}
+
+	result, err := client.Complete(context.Background(), Options{UserPrompt: "ls"})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.Fallback != 1 || result.URL != client.config.Fallbacks[0].URL {
+		t.Errorf("Fallback = %d, URL = %s, want the first fallback", result.Fallback, result.URL)
+	}
+	// 还有备用模型时遇到限流不重试
+	if got := primaryRequests.Load(); got != 1 {
+		t.Errorf("primary requests = %d, want 1", got)
+	}
+	if got := header.Get("Authorization"); got != "" {
+		t.Errorf("fallback received Authorization %q, want none", got)
+	}
+	if got := header.Get("X-Tenant-Token"); got != "" {
+		t.Errorf("fallback received X-Tenant-Token %q, want none", got)
+	}
+}
@@ -0,0 +1,156 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"AI-Shell/internal/i18n"
+)
+
+// maxErrorBodySize 读取错误响应体的上限，错误响应不应该很大
+const maxErrorBodySize = 64 * 1024
+
+// APIError 表示 API 返回的非 200 响应
+type APIError struct {
+	StatusCode int
+	Type       string        // 错误类型，如 invalid_request_error
+	Code       string        // 错误代码，如 insufficient_quota
+	Message    string        // 服务端返回的错误信息
+	RetryAfter time.Duration // 服务端要求的等待时间，0 表示未指定
+}
+
+func (e *APIError) Error() string {
+	return i18n.Sprintf("API请求失败: 状态码 %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable 判断错误是否可能在重试后消失
+func (e *APIError) Retryable() bool {
+	// 额度用尽时同样返回 429，但重试没有意义
+	if e.Code == "insufficient_quota" {
+		return false
+	}
+	return retryableStatus[e.StatusCode]
+}
+
+// retryableStatus 为可以重试的 HTTP 状态码
+var retryableStatus = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusConflict:            true,
+	http.StatusTooEarly:            true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// parseAPIError 解析错误响应。兼容 OpenAI 的 {"error": {"message", "type", "code"}} 格式，
+// 以及部分兼容服务使用的 {"error": "..."} 和 {"message": "..."} 格式
+func parseAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var payload struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		var detail struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    any    `json:"code"` // 有的服务返回数字
+		}
+		var message string
+		switch {
+		case json.Unmarshal(payload.Error, &detail) == nil && detail.Message != "":
+			apiErr.Message, apiErr.Type = detail.Message, detail.Type
+			if detail.Code != nil {
+				apiErr.Code = fmt.Sprint(detail.Code)
+			}
+		case json.Unmarshal(payload.Error, &message) == nil && message != "":
+			apiErr.Message = message
+		default:
+			apiErr.Message = payload.Message
+		}
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if len(apiErr.Message) > 200 {
+			apiErr.Message = apiErr.Message[:200] + "..."
+		}
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	apiErr.RetryAfter = parseRetryAfter(statusCode, header, time.Now())
+	return apiErr
+}
+
+// 以下为按原因分类的 API 错误，可以通过 errors.As 匹配，
+// 也可以通过 errors.As 匹配 *APIError 获取状态码和原始错误信息
+
+// AuthError API 密钥无效或没有权限
+type AuthError struct{ *APIError }
+
+// QuotaError 账户额度已用尽
+type QuotaError struct{ *APIError }
+
+// RateLimitError 请求过于频繁
+type RateLimitError struct{ *APIError }
+
+// ModelNotFoundError 模型不存在或当前账户无权使用
+type ModelNotFoundError struct{ *APIError }
+
+// ContextLengthError 请求超出模型的上下文长度
+type ContextLengthError struct{ *APIError }
+
+// ContentFilterError 请求或回复被内容审核拦截
+type ContentFilterError struct{ *APIError }
+
+// ServerError 服务端错误
+type ServerError struct{ *APIError }
+
+func (e *AuthError) Unwrap() error          { return e.APIError }
+func (e *QuotaError) Unwrap() error         { return e.APIError }
+func (e *RateLimitError) Unwrap() error     { return e.APIError }
+func (e *ModelNotFoundError) Unwrap() error { return e.APIError }
+func (e *ContextLengthError) Unwrap() error { return e.APIError }
+func (e *ContentFilterError) Unwrap() error { return e.APIError }
+func (e *ServerError) Unwrap() error        { return e.APIError }
+
+// classify 根据状态码、错误类型、错误代码和错误信息将 APIError 转换为具体的错误类型，
+// 无法归类时返回原来的 *APIError
+func classify(e *APIError) error {
+	message := strings.ToLower(e.Message)
+	is := func(codes ...string) bool {
+		for _, code := range codes {
+			if e.Code == code || e.Type == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || is("invalid_api_key"):
+		return &AuthError{e}
+	case e.StatusCode == http.StatusPaymentRequired || is("insufficient_quota", "billing_hard_limit_reached"):
+		return &QuotaError{e}
+	case e.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{e}
+	case is("model_not_found") || (e.StatusCode == http.StatusNotFound && strings.Contains(message, "model")):
+		return &ModelNotFoundError{e}
+	case e.StatusCode == http.StatusRequestEntityTooLarge || is("context_length_exceeded", "string_above_max_length") ||
+		strings.Contains(message, "context length") || strings.Contains(message, "maximum context"):
+		return &ContextLengthError{e}
+	case is("content_filter", "content_policy_violation") || strings.Contains(message, "content management policy"):
+		return &ContentFilterError{e}
+	case e.StatusCode >= http.StatusInternalServerError:
+		return &ServerError{e}
+	}
+	return e
+}
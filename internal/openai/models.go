@@ -0,0 +1,199 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+const (
+	modelsCacheFile = "models_cache.json"
+	modelsCacheTTL  = 24 * time.Hour
+	ollamaPort      = "11434"
+)
+
+// Model 表示服务提供的模型
+type Model struct {
+	ID            string `json:"id"`
+	ContextLength int    `json:"context_length,omitempty"` // 上下文长度，服务没有提供时为 0
+	OwnedBy       string `json:"owned_by,omitempty"`
+}
+
+// modelsCache 为模型列表的缓存，按模型列表地址区分
+type modelsCache struct {
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+	Models  []Model   `json:"models"`
+}
+
+// ModelsURL 根据补全接口的地址推断模型列表的地址。Ollama 使用 /api/tags，
+// 其余兼容 OpenAI 的服务将 /chat/completions 替换为 /models
+func ModelsURL(apiURL string) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return "", i18n.Errorf("无效的 API 地址: %s", apiURL)
+	}
+	u.RawQuery, u.Fragment = "", ""
+
+	if isOllama(u) {
+		u.Path = "/api/tags"
+		return u.String(), nil
+	}
+	prefix, ok := strings.CutSuffix(strings.TrimSuffix(u.Path, "/"), "/chat/completions")
+	if !ok {
+		return "", i18n.Errorf("无法从 %s 推断模型列表的地址，API 地址应以 /chat/completions 结尾", apiURL)
+	}
+	u.Path = prefix + "/models"
+	return u.String(), nil
+}
+
+// isOllama 判断地址是否为 Ollama 的原生接口或默认端口
+func isOllama(u *url.URL) bool {
+	return u.Port() == ollamaPort || strings.HasPrefix(u.Path, "/api/chat") || strings.HasPrefix(u.Path, "/api/generate")
+}
+
+// Models 返回服务提供的模型，按 ID 排序。缓存未过期且 refresh 为 false 时使用配置目录中的缓存
+func (c *Client) Models(ctx context.Context, refresh bool) ([]Model, error) {
+	modelsURL, err := ModelsURL(c.config.URL)
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(config.Dir(), modelsCacheFile)
+
+	if !refresh {
+		if models, ok := loadModelsCache(cachePath, modelsURL); ok {
+			slog.Debug("使用缓存的模型列表", "path", cachePath)
+			return models, nil
+		}
+	}
+
+	models, err := c.fetchModels(ctx, modelsURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveModelsCache(cachePath, &modelsCache{URL: modelsURL, Created: time.Now(), Models: models}); err != nil {
+		slog.Debug("保存模型列表缓存失败", "error", err)
+	}
+	return models, nil
+}
+
+// CachedModels 只读取缓存的模型列表，不发送请求，缓存不存在、已过期或不是当前服务的缓存时返回 false
+func CachedModels(cfg *config.Config) ([]Model, bool) {
+	modelsURL, err := ModelsURL(cfg.URL)
+	if err != nil {
+		return nil, false
+	}
+	return loadModelsCache(filepath.Join(config.Dir(), modelsCacheFile), modelsURL)
+}
+
+func (c *Client) fetchModels(ctx context.Context, modelsURL string) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return nil, i18n.Errorf("创建请求失败: %v", err)
+	}
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, config.ExpandEnv(value))
+	}
+
+	slog.Debug("获取模型列表", "url", modelsURL)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, i18n.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, i18n.Errorf("%s 不支持获取模型列表 (状态码 %d)", modelsURL, resp.StatusCode)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, classify(parseAPIError(resp.StatusCode, resp.Header, body))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, i18n.Errorf("读取响应失败: %w", err)
+	}
+	models, err := parseModels(body)
+	if err != nil {
+		return nil, i18n.Errorf("解析模型列表失败: %v", err)
+	}
+	return models, nil
+}
+
+// parseModels 解析 OpenAI 格式的 {"data": [...]} 和 Ollama 格式的 {"models": [...]}。
+// OpenAI 不提供上下文长度，OpenRouter、vLLM 等服务使用的字段名各不相同
+func parseModels(body []byte) ([]Model, error) {
+	type entry struct {
+		ID               string `json:"id"`
+		Name             string `json:"name"` // Ollama
+		OwnedBy          string `json:"owned_by"`
+		ContextLength    int    `json:"context_length"`     // OpenRouter
+		ContextWindow    int    `json:"context_window"`     // Groq 等
+		MaxContextLength int    `json:"max_context_length"` // 部分网关
+		MaxModelLen      int    `json:"max_model_len"`      // vLLM
+	}
+	var payload struct {
+		Data   []entry `json:"data"`
+		Models []entry `json:"models"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var models []Model
+	for _, e := range append(payload.Data, payload.Models...) {
+		id := e.ID
+		if id == "" {
+			id = e.Name
+		}
+		if id == "" {
+			continue
+		}
+		models = append(models, Model{
+			ID:            id,
+			OwnedBy:       e.OwnedBy,
+			ContextLength: max(e.ContextLength, e.ContextWindow, e.MaxContextLength, e.MaxModelLen),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+func loadModelsCache(path, modelsURL string) ([]Model, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var cache modelsCache
+	if err := json.Unmarshal(data, &cache); err != nil ||
+		cache.URL != modelsURL || time.Since(cache.Created) > modelsCacheTTL {
+		return nil, false
+	}
+	return cache.Models, true
+}
+
+func saveModelsCache(path string, cache *modelsCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,40 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"AI-Shell/internal/config"
+)
+
+func TestFetchModelsSendsAuthorizationOnlyWithKey(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"data":[{"id":"llama3.2"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"sk-test", "Bearer sk-test"},
+	}
+	for _, tt := range tests {
+		client := NewClientWithTransport(&config.Config{URL: server.URL + "/v1/chat/completions", APIKey: tt.key}, http.DefaultTransport)
+		models, err := client.fetchModels(context.Background(), server.URL+"/v1/models")
+		if err != nil {
+			t.Fatalf("fetchModels() error = %v", err)
+		}
+		if len(models) != 1 || models[0].ID != "llama3.2" {
+			t.Errorf("fetchModels() = %v, want [llama3.2]", models)
+		}
+		if _, ok := header["Authorization"]; ok != (tt.want != "") || header.Get("Authorization") != tt.want {
+			t.Errorf("key %q: Authorization = %q, want %q", tt.key, header.Get("Authorization"), tt.want)
+		}
+	}
+}
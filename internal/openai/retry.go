@@ -0,0 +1,152 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxServerDelay 服务端要求等待的时间超过该值时不再重试，直接返回错误
+const maxServerDelay = time.Minute
+
+// retryPolicy 重试策略
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// retryDelay 判断错误是否可以重试，并返回重试前的等待时间。attempt 从 0 开始。
+// 服务端通过 Retry-After 等响应头指定了等待时间时优先使用，否则使用带抖动的指数退避。
+func (p retryPolicy) retryDelay(err error, attempt int) (time.Duration, bool) {
+	if attempt >= p.maxRetries || !isRetryable(err) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > maxServerDelay {
+			return 0, false
+		}
+		return apiErr.RetryAfter, true
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff 返回第 attempt 次重试前的等待时间：baseDelay * 2^attempt，不超过 maxDelay，
+// 并在 [delay/2, delay] 之间随机抖动，避免多个客户端同时重试
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.maxDelay
+	if attempt < 32 {
+		if exp := p.baseDelay << attempt; exp > 0 && exp < p.maxDelay {
+			delay = exp
+		}
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half+1)
+}
+
+// isRetryable 判断错误是否为临时性错误：限流、服务端错误、超时和连接中断
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// canFailover 判断失败后是否改用备用模型：除了临时性错误，连接不到服务的网络错误也会改用备用模型，
+// 如离线时解析域名失败。认证失败、额度用尽等服务端明确拒绝的错误不改用备用模型
+func canFailover(err error) bool {
+	if isRetryable(err) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// parseRetryAfter 从响应头中读取服务端要求的等待时间，支持 Retry-After（秒数或 HTTP 日期）、
+// retry-after-ms，以及 429 响应中 OpenAI 风格的 x-ratelimit-reset-requests 和 x-ratelimit-reset-tokens
+func parseRetryAfter(statusCode int, header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After-Ms"); value != "" {
+		if ms, err := strconv.ParseFloat(value, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil && date.After(now) {
+			return date.Sub(now)
+		}
+	}
+
+	// 这两个响应头在成功的响应中也会出现，只有被限流时才表示需要等待的时间
+	if statusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	var wait time.Duration
+	for _, name := range []string{"X-Ratelimit-Reset-Requests", "X-Ratelimit-Reset-Tokens"} {
+		if reset := parseResetDuration(header.Get(name)); reset > wait {
+			wait = reset
+		}
+	}
+	return wait
+}
+
+// parseResetDuration 解析 6m0s、20ms 这样的时长，或以秒为单位的数字
+func parseResetDuration(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return 0
+}
+
+// sleepContext 等待 d 或直到 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
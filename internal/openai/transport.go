@@ -0,0 +1,84 @@
+package openai
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+// newTransport 根据配置中的代理、CA 证书、客户端证书和证书校验设置创建 http.Transport，
+// 其余设置与 http.DefaultTransport 相同
+func newTransport(cfg *config.Config) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, i18n.Errorf("无效的代理地址: %s", cfg.Proxy)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, i18n.Errorf("不支持的代理协议: %s (可用: http, https, socks5)", proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+		slog.Debug("使用配置中的代理", "proxy", proxy.Redacted())
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(expandHome(cfg.CAFile))
+		if err != nil {
+			return nil, i18n.Errorf("读取 CA 证书失败: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.Errorf("CA 证书文件中没有有效的 PEM 证书: %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+		slog.Debug("信任额外的 CA 证书", "path", cfg.CAFile)
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, i18n.Errorf("客户端证书和私钥需要同时设置")
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(cfg.ClientCert), expandHome(cfg.ClientKey))
+		if err != nil {
+			return nil, i18n.Errorf("加载客户端证书失败: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		slog.Debug("使用客户端证书", "cert", cfg.ClientCert)
+	}
+
+	if cfg.InsecureSkipVerify {
+		slog.Warn(i18n.T("已跳过服务端证书校验，连接可能被窃听或篡改，请只在调试时使用"))
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// expandHome 将路径开头的 ~ 替换为用户主目录
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
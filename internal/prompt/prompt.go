@@ -0,0 +1,134 @@
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+const customTemplateFile = "prompt.tmpl" // 配置目录中的自定义模板文件名
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Data 为渲染系统提示模板时可用的变量
+type Data struct {
+	SystemInfo    string   // 收集到的系统信息
+	Shell         string   // 当前 shell 名称，如 bash、zsh
+	Locale        string   // 当前语言环境，如 zh_CN.UTF-8
+	Language      string   // 模型回复 msg 时使用的语言，如 中文、English
+	MinCandidates int      // 最少返回的候选命令数
+	MaxCandidates int      // 最多返回的候选命令数
+	Diverse       bool     // 是否要求候选命令使用不同的工具或写法
+	Confidence    bool     // 是否要求模型给出每条命令的置信度
+	Rules         []string // 用户配置和项目配置中的额外规则
+}
+
+// NewData 根据配置和系统信息构建模板变量
+func NewData(cfg *config.Config, systemInfo string) Data {
+	minCandidates, maxCandidates := cfg.CandidateRange()
+	return Data{
+		SystemInfo:    systemInfo,
+		Shell:         filepath.Base(os.Getenv("SHELL")),
+		Locale:        os.Getenv("LANG"),
+		Language:      i18n.DisplayName(),
+		MinCandidates: minCandidates,
+		MaxCandidates: maxCandidates,
+		Diverse:       cfg.Diverse,
+		Confidence:    cfg.SortByConfidence,
+		Rules:         cfg.Instructions,
+	}
+}
+
+// BuiltinNames 返回所有内置模板的名称
+func BuiltinNames() []string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".tmpl"))
+	}
+	return names
+}
+
+// Builtin 返回指定名称的内置模板内容
+func Builtin(name string) (string, error) {
+	data, err := builtinTemplates.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return "", i18n.Errorf("内置模板不存在: %s (可用: %s)", name, strings.Join(BuiltinNames(), ", "))
+	}
+	return string(data), nil
+}
+
+// CustomPath 返回自定义模板文件的路径
+func CustomPath() string {
+	return filepath.Join(config.Dir(), customTemplateFile)
+}
+
+// Source 返回当前生效的模板内容。
+// 配置目录中存在自定义模板时使用自定义模板，否则使用 BuiltinName 选定的内置模板。
+func Source(cfg *config.Config) (source string, custom bool, err error) {
+	data, err := os.ReadFile(CustomPath())
+	if err == nil {
+		return string(data), true, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", false, i18n.Errorf("读取自定义模板失败: %v", err)
+	}
+
+	source, err = Builtin(BuiltinName(cfg))
+	return source, false, err
+}
+
+// BuiltinName 返回配置选定的内置模板名称，未设置时跟随界面语言
+func BuiltinName(cfg *config.Config) string {
+	if cfg.Prompt != "" {
+		return cfg.Prompt
+	}
+	return i18n.Language()
+}
+
+// Build 渲染当前生效的模板，得到最终的系统提示
+func Build(cfg *config.Config, data Data) (string, error) {
+	source, _, err := Source(cfg)
+	if err != nil {
+		return "", err
+	}
+	return Render(source, data)
+}
+
+// Render 使用给定变量渲染模板内容
+func Render(source string, data Data) (string, error) {
+	tmpl, err := Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", i18n.Errorf("渲染提示模板失败: %v", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Parse 解析模板内容，用于在保存前校验模板语法
+func Parse(source string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, i18n.Errorf("解析提示模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
+// Reset 删除自定义模板，恢复使用内置模板
+func Reset() error {
+	if err := os.Remove(CustomPath()); err != nil && !os.IsNotExist(err) {
+		return i18n.Errorf("删除自定义模板失败: %v", err)
+	}
+	return nil
+}
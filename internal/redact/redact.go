@@ -0,0 +1,200 @@
+// Package redact 在发送给 API 之前隐藏提示中的敏感信息，
+// 包括密钥和令牌、用户主目录和用户名，以及敏感的文件名。
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+// Placeholder 替换敏感内容的占位符
+const Placeholder = "[REDACTED]"
+
+// secretRule 匹配敏感内容的正则，keepGroup 为需要保留的前缀分组编号，0 表示整体替换
+type secretRule struct {
+	pattern   *regexp.Regexp
+	keepGroup int
+}
+
+// builtinSecretRules 为内置的密钥和令牌规则
+var builtinSecretRules = []secretRule{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), 0},
+	{regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), 0},                                         // AWS Access Key
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`), 0},                                      // OpenAI、Anthropic 等
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{30,}\b`), 0},                               // GitHub
+	{regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`), 0},                             // GitHub
+	{regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`), 0},                                 // GitLab
+	{regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`), 0},                               // Slack
+	{regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0},                                    // Google
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`), 0}, // JWT
+	{regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/-]{16,}=*`), 1},
+	{regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/\s@]+:)[^@\s]+@`), 1}, // URL 中的密码
+	{regexp.MustCompile(`(?i)(\b[A-Z0-9_.-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key)[A-Z0-9_.-]*\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s"']+)`), 1},
+	{regexp.MustCompile(`(--password[= ])('[^']*'|"[^"]*"|[^\s]+)`), 1},
+}
+
+// DefaultDenyFiles 为默认隐藏的文件名模式，文件名本身就可能泄露敏感信息
+var DefaultDenyFiles = []string{
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "*.keystore", "*.jks", "*.kdbx",
+	"id_rsa*", "id_dsa*", "id_ecdsa*", "id_ed25519*", ".netrc", ".pgpass", "credentials", "credentials.*",
+	".htpasswd", "*.tfstate", "*.tfvars",
+}
+
+// Redactor 按规则隐藏文本中的敏感信息
+type Redactor struct {
+	secretRules []secretRule
+	denyFiles   []string
+	homePattern *regexp.Regexp
+	userPattern *regexp.Regexp
+	userMask    string
+}
+
+// New 根据配置创建 Redactor，配置中禁用脱敏时返回 nil
+func New(cfg config.RedactConfig) (*Redactor, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+
+	redactor := &Redactor{
+		secretRules: append([]secretRule(nil), builtinSecretRules...),
+		denyFiles:   append(append([]string(nil), DefaultDenyFiles...), cfg.DenyFiles...),
+	}
+
+	for _, pattern := range cfg.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, i18n.Errorf("无效的脱敏规则 %s: %v", pattern, err)
+		}
+		redactor.secretRules = append(redactor.secretRules, secretRule{pattern: compiled})
+	}
+	for _, pattern := range cfg.DenyFiles {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, i18n.Errorf("无效的文件名模式 %s: %v", pattern, err)
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "/" {
+		redactor.setHome(filepath.Clean(home))
+	}
+	if username := currentUsername(); username != "" {
+		redactor.setUsername(username, cfg.HashUsername)
+	}
+
+	return redactor, nil
+}
+
+// setHome 设置需要替换为 ~ 的主目录。主目录只在路径结束处替换，
+// 避免 /home/dev 把同前缀的 /home/developer 替换为 ~eloper
+func (r *Redactor) setHome(home string) {
+	r.homePattern = regexp.MustCompile(`(?m)` + regexp.QuoteMeta(home) + `(/|[\s:"',]|$)`)
+}
+
+// setUsername 设置需要隐藏的用户名。用户名只在身份和路径中替换，如 id 输出的 uid=1000(name)
+// 和 /home/name；单独出现时可能是发行版、工具等普通单词，如 ubuntu、node、dev，替换后会丢失上下文
+func (r *Redactor) setUsername(username string, hash bool) {
+	r.userPattern = regexp.MustCompile(`(?m)(\(|/home/|/Users/)` + regexp.QuoteMeta(username) + `(\)|/|[\s:"',]|$)`)
+	r.userMask = "<user>"
+	if hash {
+		// 哈希后的用户名在多次请求之间保持一致，模型仍能区分不同用户
+		sum := sha256.Sum256([]byte(username))
+		r.userMask = "user-" + hex.EncodeToString(sum[:4])
+	}
+}
+
+func currentUsername() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Redact 返回隐藏敏感信息后的文本和被替换的次数，用于系统信息等自动收集的上下文
+func (r *Redactor) Redact(text string) (string, int) {
+	if r == nil {
+		return text, 0
+	}
+
+	count := 0
+	text = redactSecrets(text, r.secretRules, &count)
+	text = r.redactFiles(text, &count)
+
+	if r.homePattern != nil {
+		text = r.homePattern.ReplaceAllStringFunc(text, func(match string) string {
+			count++
+			return "~" + r.homePattern.FindStringSubmatch(match)[1]
+		})
+	}
+
+	if r.userPattern != nil {
+		text = r.userPattern.ReplaceAllStringFunc(text, func(match string) string {
+			count++
+			groups := r.userPattern.FindStringSubmatch(match)
+			return groups[1] + r.userMask + groups[2]
+		})
+	}
+
+	return text, count
+}
+
+// RedactRequest 只隐藏用户输入的请求中的密钥和令牌。请求中的文件名和路径是用户有意提到的，
+// 替换为占位符后模型只能针对占位符生成命令
+func (r *Redactor) RedactRequest(text string) (string, int) {
+	if r == nil {
+		return text, 0
+	}
+	count := 0
+	return redactSecrets(text, r.secretRules, &count), count
+}
+
+// Secrets 只隐藏文本中的密钥和令牌，供收集器在输出前先行处理
+func Secrets(text string) string {
+	count := 0
+	return redactSecrets(text, builtinSecretRules, &count)
+}
+
+func redactSecrets(text string, rules []secretRule, count *int) string {
+	for _, rule := range rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			*count++
+			if rule.keepGroup == 0 {
+				return Placeholder
+			}
+			groups := rule.pattern.FindStringSubmatch(match)
+			return groups[rule.keepGroup] + Placeholder
+		})
+	}
+	return text
+}
+
+// tokenPattern 匹配以空白分隔的单词，用于查找文件名
+var tokenPattern = regexp.MustCompile(`\S+`)
+
+// redactFiles 隐藏与文件名模式匹配的单词，保留 ls -F 风格的类型标记
+func (r *Redactor) redactFiles(text string, count *int) string {
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		name := strings.TrimRight(token, "/*@=|")
+		suffix := token[len(name):]
+		if r.isDeniedFile(filepath.Base(name)) {
+			*count++
+			return Placeholder + suffix
+		}
+		return token
+	})
+}
+
+func (r *Redactor) isDeniedFile(name string) bool {
+	for _, pattern := range r.denyFiles {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
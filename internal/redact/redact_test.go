@@ -0,0 +1,73 @@
+package redact
+
+import (
+	"testing"
+
+	"AI-Shell/internal/config"
+)
+
+func newTestRedactor(t *testing.T, username string) *Redactor {
+	t.Helper()
+	redactor, err := New(config.RedactConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	redactor.setHome("/home/" + username)
+	redactor.setUsername(username, false)
+	return redactor
+}
+
+func TestRedactUsernameOnlyInIdentityAndPaths(t *testing.T) {
+	tests := []struct {
+		username string
+		text     string
+		want     string
+	}{
+		{"ubuntu", "发行版ID: ubuntu", "发行版ID: ubuntu"},
+		{"ubuntu", "uid=1000(ubuntu) gid=1000(ubuntu) groups=1000(ubuntu),27(sudo)", "uid=1000(<user>) gid=1000(<user>) groups=1000(<user>),27(sudo)"},
+		{"node", "node(20.11.0) npm(10.2.4)", "node(20.11.0) npm(10.2.4)"},
+		{"dev", "ls > /dev/null", "ls > /dev/null"},
+		{"dev", "/home/dev/project", "~/project"},
+		{"dev", "owner: /home/dev", "owner: ~"},
+		{"dev", "cd /home/developer/app && ls /home/dev2", "cd /home/developer/app && ls /home/dev2"},
+		{"dev", "HOME='/home/dev' PATH=/home/dev/bin:/usr/bin", "HOME='~' PATH=~/bin:/usr/bin"},
+		{"alice", "/mnt/old/Users/alice/notes", "/mnt/old/Users/<user>/notes"},
+		{"alice", "/Users/alice", "/Users/<user>"},
+		{"alice", "alice-tools", "alice-tools"},
+	}
+	for _, tt := range tests {
+		got, _ := newTestRedactor(t, tt.username).Redact(tt.text)
+		if got != tt.want {
+			t.Errorf("Redact(%q) with user %s = %q, want %q", tt.text, tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestRedactRequestKeepsFileNames(t *testing.T) {
+	redactor := newTestRedactor(t, "alice")
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"chmod 600 ~/.ssh/id_rsa", "chmod 600 ~/.ssh/id_rsa"},
+		{"把 .env 复制到 /home/alice/app", "把 .env 复制到 /home/alice/app"},
+		{"用 token=abcdef123456 调用接口", "用 token=" + Placeholder + " 调用接口"},
+	}
+	for _, tt := range tests {
+		if got, _ := redactor.RedactRequest(tt.text); got != tt.want {
+			t.Errorf("RedactRequest(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	// 收集到的上下文仍然隐藏敏感文件名
+	if got, _ := redactor.Redact("id_rsa  id_rsa.pub  notes.txt"); got != Placeholder+"  "+Placeholder+"  notes.txt" {
+		t.Errorf("Redact(listing) = %q", got)
+	}
+}
+
+func TestRedactCountsOnlyWholeHomePaths(t *testing.T) {
+	_, count := newTestRedactor(t, "dev").Redact("/home/developer /home/dev2 /home/dev/app")
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+}
@@ -0,0 +1,158 @@
+package system
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+// Collector 收集一类上下文信息，所有已启用收集器的输出会拼接为发送给模型的系统信息
+type Collector interface {
+	// Name 返回收集器名称，用于配置中的启用开关和 token 预算
+	Name() string
+	// Title 返回输出中的段落标题，如 [pwd]
+	Title() string
+	// Collect 收集上下文信息，返回空字符串时该段落不会出现在输出中
+	Collect(ctx context.Context, req *Request) (string, error)
+}
+
+// Request 为一次收集提供的参数
+type Request struct {
+	Query  string         // 用户的自然语言请求
+	Config *config.Config // 当前生效的配置
+}
+
+// Defaults 收集器在配置中未设置时使用的默认值
+type Defaults struct {
+	Enabled bool
+	Budget  int           // token 预算，0 表示不限制
+	Timeout time.Duration // 超时时间，0 表示使用配置中的默认超时时间
+}
+
+// Registration 为注册表中的一项
+type Registration struct {
+	Collector Collector
+	Defaults  Defaults
+	External  bool // 是否为用户配置的外部收集器
+}
+
+// externalDefaults 为外部收集器的默认值，用户添加即表示希望启用
+var externalDefaults = Defaults{Enabled: true, Budget: 500}
+
+var registry []Registration
+
+// Register 注册收集器，输出按注册顺序排列
+func Register(collector Collector, defaults Defaults) {
+	registry = append(registry, Registration{Collector: collector, Defaults: defaults})
+}
+
+// Registrations 返回内置收集器和配置中的外部收集器
+func Registrations(cfg *config.Config) []Registration {
+	registrations := append([]Registration(nil), registry...)
+	for _, external := range cfg.ExternalCollectors {
+		registrations = append(registrations, Registration{
+			Collector: &scriptCollector{name: external.Name, command: external.Command},
+			Defaults:  externalDefaults,
+			External:  true,
+		})
+	}
+	return registrations
+}
+
+// Settings 返回合并了配置和默认值后的启用状态与 token 预算
+func (r Registration) Settings(cfg *config.Config) (enabled bool, budget int) {
+	settings := cfg.CollectorSettings(r.Collector.Name())
+	enabled, budget = r.Defaults.Enabled, r.Defaults.Budget
+	if settings.Enabled != nil {
+		enabled = *settings.Enabled
+	}
+	if settings.Budget > 0 {
+		budget = settings.Budget
+	}
+	return enabled, budget
+}
+
+// Timeout 返回收集器的超时时间，优先级为收集器配置、收集器默认值、全局配置
+func (r Registration) Timeout(cfg *config.Config) time.Duration {
+	if settings := cfg.CollectorSettings(r.Collector.Name()); settings.Timeout > 0 {
+		return time.Duration(settings.Timeout) * time.Millisecond
+	}
+	if r.Defaults.Timeout > 0 {
+		return r.Defaults.Timeout
+	}
+	if cfg.CollectorTimeout > 0 {
+		return time.Duration(cfg.CollectorTimeout) * time.Millisecond
+	}
+	return config.DefaultCollectorTimeout * time.Millisecond
+}
+
+// funcCollector 将普通函数适配为收集器
+type funcCollector struct {
+	name    string
+	title   string
+	collect func(ctx context.Context, req *Request) (string, error)
+}
+
+func (c *funcCollector) Name() string  { return c.name }
+func (c *funcCollector) Title() string { return c.title }
+
+func (c *funcCollector) Collect(ctx context.Context, req *Request) (string, error) {
+	return c.collect(ctx, req)
+}
+
+// scriptCollector 执行用户脚本并将标准输出作为上下文
+type scriptCollector struct {
+	name    string
+	command string
+}
+
+func (c *scriptCollector) Name() string  { return c.name }
+func (c *scriptCollector) Title() string { return "[" + c.name + "]" }
+
+func (c *scriptCollector) Collect(ctx context.Context, req *Request) (string, error) {
+	output, err := exec.CommandContext(ctx, "sh", "-c", c.command).Output()
+	if err != nil {
+		return "", i18n.Errorf("执行外部收集器 %s 失败: %v", c.name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// estimateTokens 粗略估算文本的 token 数：ASCII 约 4 个字符一个 token，其他字符约一个字符一个 token
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// truncateToBudget 将文本截断到 token 预算以内，并在末尾标注已截断
+func truncateToBudget(text string, budget int) string {
+	if budget <= 0 || estimateTokens(text) <= budget {
+		return text
+	}
+
+	marker := i18n.T("...(已截断)")
+	limit := budget - estimateTokens(marker)
+	ascii, other := 0, 0
+	for i, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		if (ascii+3)/4+other > limit {
+			return text[:i] + marker
+		}
+	}
+	return text
+}
@@ -0,0 +1,244 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Environment 存储运行环境的探测结果，字段为空表示未检测到
+type Environment struct {
+	Container  string // 容器类型，如 docker、podman、systemd-nspawn、lxc
+	Kubernetes bool   // 是否运行在 Kubernetes Pod 中
+	WSL        string // WSL1 或 WSL2
+	VM         string // 虚拟化平台，如 KVM、VMware
+	SSH        bool   // 是否为 SSH 会话
+	PID1       string // PID 1 的进程名
+	InitSystem string // init 系统，如 systemd、openrc，为空表示没有 init 系统
+	Libc       string // glibc 或 musl
+	BusyBox    bool   // 基础命令是否由 BusyBox 提供
+}
+
+// vmVendors 为 DMI 信息中的关键字和对应的虚拟化平台
+var vmVendors = []struct{ keyword, name string }{
+	{"QEMU", "QEMU"},
+	{"KVM", "KVM"},
+	{"VMware", "VMware"},
+	{"VirtualBox", "VirtualBox"},
+	{"innotek", "VirtualBox"},
+	{"Xen", "Xen"},
+	{"Parallels", "Parallels"},
+	{"Amazon EC2", "Amazon EC2"},
+	{"Google Compute Engine", "Google Compute Engine"},
+	{"Virtual Machine", "Hyper-V"},
+}
+
+func collectEnvironment(ctx context.Context, req *Request) (string, error) {
+	return DetectEnvironment().String(), nil
+}
+
+// DetectEnvironment 探测容器、虚拟机、WSL、SSH 会话、init 系统和 libc 等运行环境信息
+func DetectEnvironment() *Environment {
+	env := &Environment{
+		Container:  detectContainer(),
+		Kubernetes: fileExists("/var/run/secrets/kubernetes.io/serviceaccount") || os.Getenv("KUBERNETES_SERVICE_HOST") != "",
+		WSL:        detectWSL(),
+		SSH:        os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "",
+		PID1:       readFirstLine("/proc/1/comm"),
+		Libc:       detectLibc(),
+		BusyBox:    isBusyBox(),
+	}
+	if env.Container == "" && env.WSL == "" {
+		env.VM = detectVM()
+	}
+	env.InitSystem = detectInitSystem(env.PID1, env.WSL)
+	return env
+}
+
+// String 将探测结果格式化为发送给模型的文本
+func (e *Environment) String() string {
+	var lines []string
+	if e.Container != "" {
+		lines = append(lines, "容器: "+e.Container)
+	}
+	if e.Kubernetes {
+		lines = append(lines, "Kubernetes Pod: 是")
+	}
+	if e.WSL != "" {
+		lines = append(lines, "WSL: "+e.WSL)
+	}
+	if e.VM != "" {
+		lines = append(lines, "虚拟机: "+e.VM)
+	}
+	if e.SSH {
+		lines = append(lines, "SSH 会话: 是")
+	}
+	if e.PID1 != "" {
+		lines = append(lines, "PID 1: "+e.PID1)
+	}
+	if e.InitSystem != "" {
+		lines = append(lines, "init 系统: "+e.InitSystem)
+	} else if e.PID1 != "" {
+		lines = append(lines, "init 系统: 无，不要使用 systemctl、service、journalctl 管理服务")
+	}
+	if e.Libc != "" {
+		lines = append(lines, "libc: "+e.Libc)
+	}
+	if e.BusyBox {
+		lines = append(lines, "基础命令由 BusyBox 提供，只能使用 POSIX 选项，不要使用 GNU 专用选项")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// detectContainer 根据标记文件、container 环境变量和 cgroup 路径判断容器类型
+func detectContainer() string {
+	switch {
+	case fileExists("/run/.containerenv"):
+		return "podman"
+	case fileExists("/.dockerenv"):
+		return "docker"
+	}
+	// systemd 和 systemd-nspawn 会写入该文件
+	if container := readFirstLine("/run/systemd/container"); container != "" {
+		return container
+	}
+	if container := os.Getenv("container"); container != "" {
+		return container
+	}
+
+	markers := []struct{ keyword, name string }{
+		{"libpod", "podman"},
+		{"docker", "docker"},
+		{"kubepods", "kubernetes"},
+		{"containerd", "containerd"},
+		{"lxc", "lxc"},
+	}
+	for _, path := range []string{"/proc/1/cgroup", "/proc/self/cgroup"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, marker := range markers {
+			if strings.Contains(string(data), marker.keyword) {
+				return marker.name
+			}
+		}
+	}
+	return ""
+}
+
+// detectWSL 根据内核版本字符串判断是否运行在 WSL 中
+func detectWSL() string {
+	version := strings.ToLower(readFirstLine("/proc/version"))
+	if !strings.Contains(version, "microsoft") {
+		if os.Getenv("WSL_DISTRO_NAME") != "" {
+			return "WSL"
+		}
+		return ""
+	}
+	if strings.Contains(version, "wsl2") || strings.Contains(version, "microsoft-standard") {
+		return "WSL2"
+	}
+	return "WSL1"
+}
+
+// detectVM 根据 DMI 信息和 CPU 的 hypervisor 标志判断虚拟化平台
+func detectVM() string {
+	dmi := readFirstLine("/sys/class/dmi/id/sys_vendor") + " " + readFirstLine("/sys/class/dmi/id/product_name")
+	for _, vendor := range vmVendors {
+		if strings.Contains(dmi, vendor.keyword) {
+			return vendor.name
+		}
+	}
+
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "flags") {
+			if strings.Contains(line, " hypervisor") {
+				return "未知平台"
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// detectInitSystem 判断负责管理服务的 init 系统，wsl 为 detectWSL 的结果
+func detectInitSystem(pid1, wsl string) string {
+	// 与 sd_booted() 的判断方式相同
+	if fileExists("/run/systemd/system") {
+		return "systemd"
+	}
+	// 未启用 systemd 的 WSL 中，PID 1 是微软的 init，不管理服务
+	if wsl != "" {
+		return ""
+	}
+
+	switch {
+	case pid1 == "systemd":
+		return "systemd"
+	case pid1 == "init" && (fileExists("/run/openrc") || fileExists("/sbin/openrc-run")):
+		return "openrc"
+	case pid1 == "init":
+		return "sysvinit"
+	case pid1 == "runit" || pid1 == "runit-init":
+		return "runit"
+	case pid1 == "s6-svscan":
+		return "s6"
+	}
+	// 容器中的 tini、dumb-init 等精简 init 不管理服务
+	return ""
+}
+
+// detectLibc 根据动态链接器判断 libc 类型
+func detectLibc() string {
+	patterns := []struct{ pattern, name string }{
+		{"/lib/ld-musl-*.so.1", "musl"},
+		{"/lib*/ld-linux*.so.*", "glibc"},
+		{"/lib/*-linux-gnu*/ld-linux*.so.*", "glibc"},
+		{"/usr/lib*/ld-linux*.so.*", "glibc"},
+	}
+	for _, p := range patterns {
+		if matches, _ := filepath.Glob(p.pattern); len(matches) > 0 {
+			return p.name
+		}
+	}
+	return ""
+}
+
+// isBusyBox 判断 ls 是否为指向 BusyBox 的链接
+func isBusyBox() bool {
+	path, err := exec.LookPath("ls")
+	if err != nil {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(filepath.Base(resolved), "busybox")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func readFirstLine(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,25 @@
+package system
+
+import "testing"
+
+func TestDetectInitSystem(t *testing.T) {
+	if fileExists("/run/systemd/system") {
+		t.Skip("系统由 systemd 引导，结果总是 systemd")
+	}
+	tests := []struct {
+		pid1 string
+		wsl  string
+		want string
+	}{
+		{"init", "WSL1", ""},
+		{"init", "WSL2", ""},
+		{"systemd", "", "systemd"},
+		{"runit", "", "runit"},
+		{"tini", "", ""},
+	}
+	for _, tt := range tests {
+		if got := detectInitSystem(tt.pid1, tt.wsl); got != tt.want {
+			t.Errorf("detectInitSystem(%q, %q) = %q, want %q", tt.pid1, tt.wsl, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"AI-Shell/internal/i18n"
+)
+
+const (
+	maxGitDirtyFiles    = 20 // 最多列出的未提交文件数
+	maxGitRecentCommits = 5  // 最多列出的最近提交数
+)
+
+// GitInfo 存储当前 git 仓库的状态
+type GitInfo struct {
+	Branch        string   // 当前分支，分离头指针时为空
+	Head          string   // 当前提交的短哈希
+	Upstream      string   // 上游分支
+	Ahead, Behind int      // 相对上游领先和落后的提交数
+	DirtyFiles    []string // git status --porcelain 格式的未提交文件
+	DirtyTotal    int      // 未提交文件总数
+	StatusKnown   bool     // 是否获取到了工作区状态，直接读取 .git 目录时无法获取
+	RecentCommits []string // 最近提交的标题
+	Remotes       []string // 远程仓库，格式为 名称 地址
+	InProgress    string   // 正在进行的操作，如 rebase、merge
+}
+
+func collectGit(ctx context.Context, req *Request) (string, error) {
+	gitDir, err := findGitDir(".")
+	if err != nil || gitDir == "" {
+		return "", err
+	}
+
+	// 优先使用 git 命令，没有安装或执行失败（如 safe.directory 限制）时直接读取 .git 目录
+	info, err := gitInfoFromCommand(ctx)
+	if err != nil {
+		slog.Debug("通过 git 命令获取仓库状态失败，改为读取 .git 目录", "error", err)
+		info, err = gitInfoFromDir(gitDir)
+	}
+	if err != nil {
+		return "", err
+	}
+	info.InProgress = gitOperationInProgress(gitDir)
+
+	return info.String(), nil
+}
+
+// String 将仓库状态格式化为发送给模型的文本
+func (g *GitInfo) String() string {
+	var lines []string
+
+	branch := g.Branch
+	if branch == "" {
+		branch = fmt.Sprintf("(分离头指针 %s)", g.Head)
+	}
+	lines = append(lines, "分支: "+branch)
+
+	if g.Upstream != "" {
+		lines = append(lines, fmt.Sprintf("上游: %s (领先 %d, 落后 %d)", g.Upstream, g.Ahead, g.Behind))
+	}
+	if g.InProgress != "" {
+		lines = append(lines, "进行中: "+g.InProgress)
+	}
+	if len(g.Remotes) > 0 {
+		lines = append(lines, "远程仓库:")
+		lines = append(lines, indent(g.Remotes)...)
+	}
+	if g.DirtyTotal > 0 {
+		lines = append(lines, fmt.Sprintf("未提交的文件 (%d):", g.DirtyTotal))
+		lines = append(lines, indent(g.DirtyFiles)...)
+		if g.DirtyTotal > len(g.DirtyFiles) {
+			lines = append(lines, fmt.Sprintf("  ...省略 %d 个", g.DirtyTotal-len(g.DirtyFiles)))
+		}
+	} else if g.StatusKnown {
+		lines = append(lines, "工作区干净")
+	}
+	if len(g.RecentCommits) > 0 {
+		lines = append(lines, "最近提交:")
+		lines = append(lines, indent(g.RecentCommits)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func indent(items []string) []string {
+	indented := make([]string, len(items))
+	for i, item := range items {
+		indented[i] = "  " + item
+	}
+	return indented
+}
+
+// findGitDir 从 dir 开始逐级向上查找 .git，返回 git 目录路径；不在仓库中时返回空字符串
+func findGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			if info.IsDir() {
+				return candidate, nil
+			}
+			// 工作树和子模块中的 .git 是一个指向真实 git 目录的文件
+			return readGitDirFile(candidate)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func readGitDirFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return gitDir, nil
+}
+
+// gitOperationInProgress 根据 git 目录中的标记文件判断是否有未完成的操作
+func gitOperationInProgress(gitDir string) string {
+	markers := []struct{ path, operation string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+		{"BISECT_LOG", "bisect"},
+	}
+
+	// 工作树的 rebase 等状态保存在自己的 git 目录中，这里无需区分
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.path)); err == nil {
+			return marker.operation
+		}
+	}
+	return ""
+}
+
+// gitInfoFromCommand 通过 git 命令获取仓库状态
+func gitInfoFromCommand(ctx context.Context) (*GitInfo, error) {
+	info := &GitInfo{StatusKnown: true}
+
+	status, err := runGit(ctx, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(status, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			info.Head = shortHash(strings.TrimPrefix(line, "# branch.oid "))
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				info.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			info.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &info.Ahead, &info.Behind)
+		case line != "" && !strings.HasPrefix(line, "#"):
+			info.addDirtyFile(porcelainV2ToShort(line))
+		}
+	}
+
+	if log, err := runGit(ctx, "log", fmt.Sprintf("-%d", maxGitRecentCommits), "--format=%h %s"); err == nil && log != "" {
+		info.RecentCommits = strings.Split(log, "\n")
+	}
+
+	if remotes, err := runGit(ctx, "remote", "-v"); err == nil && remotes != "" {
+		for _, line := range strings.Split(remotes, "\n") {
+			// 每个远程仓库有 fetch 和 push 两行，只保留 fetch
+			if strings.HasSuffix(line, "(fetch)") {
+				info.Remotes = append(info.Remotes, strings.Join(strings.Fields(strings.TrimSuffix(line, "(fetch)")), " "))
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func runGit(ctx context.Context, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// porcelainV2ToShort 将 porcelain v2 的条目转换为 "XY 路径" 的简短格式
+func porcelainV2ToShort(line string) string {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "1":
+		// 1 XY sub mH mI mW hH hI path
+		if len(fields) >= 9 {
+			return fields[1] + " " + strings.Join(fields[8:], " ")
+		}
+	case "2":
+		// 2 XY sub mH mI mW hH hI Xscore path\torigPath
+		if len(fields) >= 10 {
+			return fields[1] + " " + strings.ReplaceAll(strings.Join(fields[9:], " "), "\t", " <- ")
+		}
+	case "u":
+		if len(fields) >= 11 {
+			return fields[1] + " " + strings.Join(fields[10:], " ")
+		}
+	case "?":
+		return "?? " + strings.Join(fields[1:], " ")
+	}
+	return line
+}
+
+func (g *GitInfo) addDirtyFile(file string) {
+	g.DirtyTotal++
+	if len(g.DirtyFiles) < maxGitDirtyFiles {
+		g.DirtyFiles = append(g.DirtyFiles, file)
+	}
+}
+
+// gitInfoFromDir 在没有安装 git 时直接读取 .git 目录，只能获取分支、上游和远程仓库
+func gitInfoFromDir(gitDir string) (*GitInfo, error) {
+	info := &GitInfo{}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return nil, i18n.Errorf("读取 git HEAD 失败: %v", err)
+	}
+	headRef := strings.TrimSpace(string(head))
+	if ref, ok := strings.CutPrefix(headRef, "ref: refs/heads/"); ok {
+		info.Branch = ref
+	} else {
+		info.Head = shortHash(headRef)
+	}
+
+	// 工作树的 config 位于主仓库的 git 目录中
+	configPath := filepath.Join(gitDir, "config")
+	if commonDir, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		configPath = filepath.Join(gitDir, strings.TrimSpace(string(commonDir)), "config")
+	}
+	remotes, upstream := parseGitConfig(configPath, info.Branch)
+	info.Remotes = remotes
+	info.Upstream = upstream
+
+	return info, nil
+}
+
+// parseGitConfig 从 git 配置文件中读取远程仓库和指定分支的上游
+func parseGitConfig(path, branch string) (remotes []string, upstream string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, ""
+	}
+	defer file.Close()
+
+	var section, remoteName, branchRemote, branchMerge string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			remoteName = ""
+			if name, ok := strings.CutPrefix(section, "remote "); ok {
+				remoteName = strings.Trim(name, `"`)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch {
+		case remoteName != "" && key == "url":
+			remotes = append(remotes, remoteName+" "+value)
+		case branch != "" && section == fmt.Sprintf(`branch "%s"`, branch) && key == "remote":
+			branchRemote = value
+		case branch != "" && section == fmt.Sprintf(`branch "%s"`, branch) && key == "merge":
+			branchMerge = strings.TrimPrefix(value, "refs/heads/")
+		}
+	}
+
+	if branchRemote != "" && branchMerge != "" {
+		upstream = branchRemote + "/" + branchMerge
+	}
+	return remotes, upstream
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
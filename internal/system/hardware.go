@@ -0,0 +1,244 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// performanceKeywords 出现在请求中时才收集硬件和资源信息。中文关键字使用完整的词，
+// 避免卡匹配网卡、核匹配审核、空间匹配命名空间
+var performanceKeywords = newKeywordMatcher(
+	[]string{"慢", "卡顿", "很卡", "卡住", "卡死", "性能", "负载", "内存", "处理器", "核数", "核心数", "并行", "并发", "线程",
+		"进程", "磁盘", "硬盘", "剩余空间", "空间不足", "硬件", "系统资源", "资源占用", "占用率", "交换分区", "交换空间", "编译"},
+	[]string{`slow\w*`, `perf\w*`, `load(s|ed|ing)?`, `loadavg`, `cpus?`, `memory`, `mem`, `ram`, `cores?`,
+		`parallel\w*`, `concurren\w*`, `threads?`, `jobs`, `-j\d*`, `disks?`, `space`, `hardware`, `resources?`,
+		`swap\w*`, `oom`, `hang(s|ing)?`, `freez\w*`, `frozen`},
+)
+
+// Hardware 存储硬件和资源使用情况
+type Hardware struct {
+	CPUModel      string
+	CPUs          int // 逻辑 CPU 数
+	PhysicalCores int // 物理核数，无法获取时为 0
+	LoadAvg       string
+	MemTotal      int64 // 单位均为字节
+	MemAvailable  int64
+	SwapTotal     int64
+	SwapFree      int64
+	CgroupCPUs    float64 // cgroup 限制的 CPU 数，0 表示不限制
+	CgroupMemory  int64   // cgroup 限制的内存，0 表示不限制
+	Disk          *DiskUsage
+	Kernel        string
+}
+
+// DiskUsage 存储文件系统的使用情况
+type DiskUsage struct {
+	MountPoint string
+	FSType     string
+	Total      int64
+	Available  int64
+}
+
+func collectHardware(ctx context.Context, req *Request) (string, error) {
+	if !performanceKeywords.match(req.Query) {
+		return "", nil
+	}
+	return GetHardware().String(), nil
+}
+
+// keywordMatcher 判断请求是否涉及某类话题，不区分大小写。中文关键字按子串匹配；
+// 英文关键字为正则表达式片段，按整个单词匹配，避免 load 匹配 download、hang 匹配 change
+type keywordMatcher struct {
+	substrings []string
+	words      *regexp.Regexp
+}
+
+func newKeywordMatcher(substrings, words []string) *keywordMatcher {
+	return &keywordMatcher{
+		substrings: substrings,
+		words:      regexp.MustCompile(`(?i)(?:^|[^a-z0-9_])(?:` + strings.Join(words, "|") + `)(?:[^a-z0-9_]|$)`),
+	}
+}
+
+func (m *keywordMatcher) match(query string) bool {
+	for _, keyword := range m.substrings {
+		if strings.Contains(query, keyword) {
+			return true
+		}
+	}
+	return m.words.MatchString(query)
+}
+
+// GetHardware 读取 /proc 和 /sys 中的 CPU、内存、负载、磁盘和内核信息，读取失败的项目留空
+func GetHardware() *Hardware {
+	hardware := &Hardware{
+		CPUs:    runtime.NumCPU(),
+		LoadAvg: readFirstLine("/proc/loadavg"),
+		Kernel:  readFirstLine("/proc/sys/kernel/osrelease"),
+	}
+	hardware.readCPUInfo()
+	hardware.readMemInfo()
+	hardware.readCgroupLimits()
+	if pwd, err := os.Getwd(); err == nil {
+		hardware.Disk = diskUsage(pwd)
+	}
+	return hardware
+}
+
+// String 将硬件信息格式化为发送给模型的简短文本
+func (h *Hardware) String() string {
+	var lines []string
+
+	cpu := fmt.Sprintf("CPU: %d 个逻辑核", h.CPUs)
+	if h.PhysicalCores > 0 {
+		cpu += fmt.Sprintf(" (%d 个物理核)", h.PhysicalCores)
+	}
+	if h.CPUModel != "" {
+		cpu += ", " + h.CPUModel
+	}
+	lines = append(lines, cpu)
+
+	if fields := strings.Fields(h.LoadAvg); len(fields) >= 4 {
+		lines = append(lines, fmt.Sprintf("负载: %s %s %s (1/5/15 分钟), 运行中/总进程 %s",
+			fields[0], fields[1], fields[2], fields[3]))
+	}
+	if h.MemTotal > 0 {
+		memory := fmt.Sprintf("内存: 总计 %s, 可用 %s", formatSize(h.MemTotal), formatSize(h.MemAvailable))
+		if h.SwapTotal > 0 {
+			memory += fmt.Sprintf(", 交换分区: 总计 %s, 已用 %s", formatSize(h.SwapTotal), formatSize(h.SwapTotal-h.SwapFree))
+		}
+		lines = append(lines, memory)
+	}
+
+	var limits []string
+	if h.CgroupCPUs > 0 {
+		limits = append(limits, "CPU "+strconv.FormatFloat(h.CgroupCPUs, 'f', -1, 64))
+	}
+	if h.CgroupMemory > 0 {
+		limits = append(limits, "内存 "+formatSize(h.CgroupMemory))
+	}
+	if len(limits) > 0 {
+		lines = append(lines, "cgroup 限制: "+strings.Join(limits, ", "))
+	}
+
+	if h.Disk != nil && h.Disk.Total > 0 {
+		used := 100 - h.Disk.Available*100/h.Disk.Total
+		lines = append(lines, fmt.Sprintf("当前目录所在磁盘: %s (%s), 总计 %s, 可用 %s, 已用 %d%%",
+			h.Disk.MountPoint, h.Disk.FSType, formatSize(h.Disk.Total), formatSize(h.Disk.Available), used))
+	}
+	if h.Kernel != "" {
+		lines = append(lines, "内核: "+h.Kernel)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readCPUInfo 从 /proc/cpuinfo 读取 CPU 型号和物理核数
+func (h *Hardware) readCPUInfo() {
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	cores := make(map[string]bool)
+	var physicalID string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "model name", "Model", "Hardware":
+			if h.CPUModel == "" {
+				h.CPUModel = strings.Join(strings.Fields(value), " ")
+			}
+		case "physical id":
+			physicalID = value
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		}
+	}
+	h.PhysicalCores = len(cores)
+}
+
+// readMemInfo 从 /proc/meminfo 读取内存和交换分区信息
+func (h *Hardware) readMemInfo() {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	fields := map[string]*int64{
+		"MemTotal":     &h.MemTotal,
+		"MemAvailable": &h.MemAvailable,
+		"SwapTotal":    &h.SwapTotal,
+		"SwapFree":     &h.SwapFree,
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if target, known := fields[key]; ok && known {
+			kb, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+			*target = kb * 1024
+		}
+	}
+}
+
+// readCgroupLimits 读取 cgroup v2 的 CPU 和内存限制，容器中的实际可用资源通常少于整机
+func (h *Hardware) readCgroupLimits() {
+	const cgroupRoot = "/sys/fs/cgroup"
+	if quota, period, ok := strings.Cut(readFirstLine(filepath.Join(cgroupRoot, "cpu.max")), " "); ok && quota != "max" {
+		q, errQ := strconv.ParseFloat(quota, 64)
+		p, errP := strconv.ParseFloat(period, 64)
+		if errQ == nil && errP == nil && p > 0 {
+			h.CgroupCPUs = q / p
+		}
+	}
+	if memory := readFirstLine(filepath.Join(cgroupRoot, "memory.max")); memory != "" && memory != "max" {
+		h.CgroupMemory, _ = strconv.ParseInt(memory, 10, 64)
+	}
+}
+
+// diskUsage 返回 dir 所在文件系统的使用情况
+func diskUsage(dir string) *DiskUsage {
+	total, available, err := statFS(dir)
+	if err != nil {
+		return nil
+	}
+	usage := &DiskUsage{Total: total, Available: available}
+	usage.MountPoint, usage.FSType = findMount(dir)
+	return usage
+}
+
+// findMount 在 /proc/self/mounts 中查找包含 dir 的最深的挂载点
+func findMount(dir string) (mountPoint, fsType string) {
+	file, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return dir, "?"
+	}
+	defer file.Close()
+
+	mountPoint, fsType = "/", "?"
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		point := fields[1]
+		if (dir == point || strings.HasPrefix(dir, strings.TrimSuffix(point, "/")+"/")) && len(point) >= len(mountPoint) {
+			mountPoint, fsType = point, fields[2]
+		}
+	}
+	return mountPoint, fsType
+}
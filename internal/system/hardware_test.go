@@ -0,0 +1,38 @@
+package system
+
+import "testing"
+
+func TestPerformanceKeywords(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"why is my build so slow", true},
+		{"show CPU and RAM usage", true},
+		{"run make -j8 with all cores", true},
+		{"check disk space", true},
+		{"the process hangs after startup", true},
+		{"performance of the web server", true},
+		{"系统很卡，看看内存", true},
+		{"查看 CPU核数", true},
+		{"磁盘剩余空间还有多少", true},
+		{"编辑器打开大文件时卡顿", true},
+		{"统计日志中出现次数最多的 IP", false},
+		{"change the file owner", false},
+		{"download the release and upload it", false},
+		{"list program params", false},
+		{"print the highest score", false},
+		{"list pods in the kube-system namespace", false},
+		{"output as --json", false},
+		{"列出网卡", false},
+		{"删除 k8s 命名空间", false},
+		{"核对两个文件的 md5", false},
+		{"审核最近的提交", false},
+		{"把信用卡号打码", false},
+	}
+	for _, tt := range tests {
+		if got := performanceKeywords.match(tt.query); got != tt.want {
+			t.Errorf("performanceKeywords.match(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
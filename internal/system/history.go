@@ -0,0 +1,274 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"AI-Shell/internal/i18n"
+	"AI-Shell/internal/redact"
+)
+
+const (
+	DefaultHistoryEntries = 10
+	historyTailBytes      = 256 * 1024 // 只读取历史文件末尾的部分，历史文件可能很大
+)
+
+// HistoryEntry 为一条 shell 历史记录
+type HistoryEntry struct {
+	Command string
+	Time    time.Time // 历史文件中没有时间戳时为零值
+}
+
+func collectHistory(ctx context.Context, req *Request) (string, error) {
+	count := req.Config.HistoryEntries
+	if count <= 0 {
+		count = DefaultHistoryEntries
+	}
+
+	entries, err := ReadHistory(os.Getenv("SHELL"), count)
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		// 命令中的密钥和令牌在进入提示之前就隐藏，不依赖发送前的脱敏设置
+		command := redact.Secrets(entry.Command)
+		if entry.Time.IsZero() {
+			lines[i] = command
+		} else {
+			lines[i] = entry.Time.Format("2006-01-02 15:04") + " " + command
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ReadHistory 读取 shell 最近的 count 条历史记录，支持 bash、zsh 和 fish。
+// 历史文件不存在时返回空列表。
+func ReadHistory(shell string, count int) ([]HistoryEntry, error) {
+	name := filepath.Base(shell)
+	path := historyFile(name)
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := readTail(path, historyTailBytes)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("读取历史记录失败: %v", err)
+	}
+
+	var entries []HistoryEntry
+	switch name {
+	case "zsh":
+		entries = parseZshHistory(data)
+	case "fish":
+		entries = parseFishHistory(data)
+	default:
+		entries = parseBashHistory(data)
+	}
+	return lastHistoryEntries(entries, count), nil
+}
+
+// historyFile 返回 shell 历史文件的路径，优先使用 $HISTFILE
+func historyFile(shell string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	switch shell {
+	case "fish":
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dataHome, "fish", "fish_history")
+	case "zsh":
+		if file := os.Getenv("HISTFILE"); file != "" {
+			return file
+		}
+		dir := os.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		return filepath.Join(dir, ".zsh_history")
+	default:
+		if file := os.Getenv("HISTFILE"); file != "" {
+			return file
+		}
+		return filepath.Join(home, ".bash_history")
+	}
+}
+
+// readTail 读取文件末尾最多 size 字节，并丢弃可能不完整的第一行
+func readTail(path string, size int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-size, 0)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return data, nil
+}
+
+// lastHistoryEntries 返回最后 count 条记录，去掉连续重复的命令和 ais 自身的调用
+func lastHistoryEntries(entries []HistoryEntry, count int) []HistoryEntry {
+	var result []HistoryEntry
+	for i := len(entries) - 1; i >= 0 && len(result) < count; i-- {
+		command := strings.TrimSpace(entries[i].Command)
+		if command == "" || command == "ais" || strings.HasPrefix(command, "ais ") {
+			continue
+		}
+		if len(result) > 0 && result[len(result)-1].Command == command {
+			continue
+		}
+		result = append(result, HistoryEntry{Command: command, Time: entries[i].Time})
+	}
+
+	// 恢复为从旧到新的顺序
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// parseBashHistory 解析 bash 历史，设置了 HISTTIMEFORMAT 时每条命令前有一行 #时间戳
+func parseBashHistory(data []byte) []HistoryEntry {
+	var entries []HistoryEntry
+	var timestamp time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if seconds, ok := strings.CutPrefix(line, "#"); ok {
+			if unix, err := strconv.ParseInt(seconds, 10, 64); err == nil {
+				timestamp = time.Unix(unix, 0)
+				continue
+			}
+		}
+		entries = append(entries, HistoryEntry{Command: line, Time: timestamp})
+		timestamp = time.Time{}
+	}
+	return entries
+}
+
+// parseZshHistory 解析 zsh 历史，支持 EXTENDED_HISTORY 格式 ": 开始时间:耗时;命令"
+// 和以反斜杠结尾的多行命令
+func parseZshHistory(data []byte) []HistoryEntry {
+	var entries []HistoryEntry
+	var current *HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(unmetafy(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if current != nil {
+			// 上一行以反斜杠结尾，当前行是同一条命令的延续
+			current.Command += "\n" + line
+		} else {
+			entry := HistoryEntry{Command: line}
+			if rest, ok := strings.CutPrefix(line, ": "); ok {
+				if meta, command, ok := strings.Cut(rest, ";"); ok {
+					start, _, _ := strings.Cut(meta, ":")
+					if unix, err := strconv.ParseInt(start, 10, 64); err == nil {
+						entry = HistoryEntry{Command: command, Time: time.Unix(unix, 0)}
+					}
+				}
+			}
+			entries = append(entries, entry)
+			current = &entries[len(entries)-1]
+		}
+
+		if strings.HasSuffix(line, `\`) {
+			current.Command = strings.TrimSuffix(current.Command, `\`)
+		} else {
+			current = nil
+		}
+	}
+	return entries
+}
+
+// unmetafy 还原 zsh 历史文件中转义的字节：0x83 之后的字节与 0x20 异或
+func unmetafy(data []byte) []byte {
+	const meta = 0x83
+	if bytes.IndexByte(data, meta) < 0 {
+		return data
+	}
+	result := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] == meta && i+1 < len(data) {
+			i++
+			result = append(result, data[i]^0x20)
+			continue
+		}
+		result = append(result, data[i])
+	}
+	return result
+}
+
+// parseFishHistory 解析 fish 历史的类 YAML 格式，每条记录以 "- cmd: " 开头，
+// 随后缩进的 "when: " 为时间戳，"paths:" 列表忽略
+func parseFishHistory(data []byte) []HistoryEntry {
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if command, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			entries = append(entries, HistoryEntry{Command: unescapeFish(command)})
+			continue
+		}
+		if when, ok := strings.CutPrefix(line, "  when: "); ok && len(entries) > 0 {
+			if unix, err := strconv.ParseInt(strings.TrimSpace(when), 10, 64); err == nil {
+				entries[len(entries)-1].Time = time.Unix(unix, 0)
+			}
+		}
+	}
+	return entries
+}
+
+// unescapeFish 还原 fish 历史中转义的换行和反斜杠
+func unescapeFish(command string) string {
+	var builder strings.Builder
+	for i := 0; i < len(command); i++ {
+		if command[i] == '\\' && i+1 < len(command) {
+			switch command[i+1] {
+			case 'n':
+				builder.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				builder.WriteByte('\\')
+				i++
+				continue
+			}
+		}
+		builder.WriteByte(command[i])
+	}
+	return builder.String()
+}
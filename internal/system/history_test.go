@@ -0,0 +1,176 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// metafy 按 zsh 写入历史文件的方式转义字节，与 unmetafy 相反
+func metafy(text string) []byte {
+	var result []byte
+	for _, b := range []byte(text) {
+		if b == 0 || (b >= 0x83 && b <= 0x9f) {
+			result = append(result, 0x83, b^0x20)
+			continue
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+func TestParseZshHistory(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []HistoryEntry
+	}{
+		{
+			name: "plain",
+			data: []byte("ls\ngit status\n"),
+			want: []HistoryEntry{{Command: "ls"}, {Command: "git status"}},
+		},
+		{
+			name: "extended",
+			data: []byte(": 1700000000:3;make test\n: 1700000060:0;echo a;b\n"),
+			want: []HistoryEntry{
+				{Command: "make test", Time: time.Unix(1700000000, 0)},
+				{Command: "echo a;b", Time: time.Unix(1700000060, 0)},
+			},
+		},
+		{
+			name: "multi-line",
+			data: []byte(": 1700000000:0;for f in *.log; do\\\n  gzip $f\\\ndone\nls\n"),
+			want: []HistoryEntry{
+				{Command: "for f in *.log; do\n  gzip $f\ndone", Time: time.Unix(1700000000, 0)},
+				{Command: "ls"},
+			},
+		},
+		{
+			name: "metafied",
+			data: append(metafy(": 1700000000:0;grep 日志 错误.txt"), '\n'),
+			want: []HistoryEntry{{Command: "grep 日志 错误.txt", Time: time.Unix(1700000000, 0)}},
+		},
+		{
+			name: "not a timestamp",
+			data: []byte(": not-a-time;ls\n"),
+			want: []HistoryEntry{{Command: ": not-a-time;ls"}},
+		},
+	}
+	for _, tt := range tests {
+		if got := parseZshHistory(tt.data); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseZshHistory() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnmetafy(t *testing.T) {
+	if got := string(unmetafy(metafy("echo 日志"))); got != "echo 日志" {
+		t.Errorf("unmetafy() = %q, want %q", got, "echo 日志")
+	}
+	// 末尾单独的 0x83 原样保留
+	if got := unmetafy([]byte{'a', 0x83}); !reflect.DeepEqual(got, []byte{'a', 0x83}) {
+		t.Errorf("unmetafy() = %v", got)
+	}
+}
+
+func TestParseFishHistory(t *testing.T) {
+	data := []byte(`- cmd: cd /tmp
+  when: 1700000000
+  paths:
+    - /tmp
+- cmd: echo "a\\b"\nls
+  when: 1700000060
+- cmd: git status
+`)
+	want := []HistoryEntry{
+		{Command: "cd /tmp", Time: time.Unix(1700000000, 0)},
+		{Command: "echo \"a\\b\"\nls", Time: time.Unix(1700000060, 0)},
+		{Command: "git status"},
+	}
+	if got := parseFishHistory(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFishHistory() = %q, want %q", got, want)
+	}
+}
+
+func TestUnescapeFish(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{`ls`, "ls"},
+		{`echo a\nb`, "echo a\nb"},
+		{`printf '\\n'`, `printf '\n'`},
+		{`grep \t file`, `grep \t file`},
+		{`echo \`, `echo \`},
+	}
+	for _, tt := range tests {
+		if got := unescapeFish(tt.command); got != tt.want {
+			t.Errorf("unescapeFish(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestParseBashHistory(t *testing.T) {
+	data := []byte("ls\n#1700000000\ngit log\n#not-a-time\n#1700000060\nmake\n")
+	want := []HistoryEntry{
+		{Command: "ls"},
+		{Command: "git log", Time: time.Unix(1700000000, 0)},
+		{Command: "#not-a-time"},
+		{Command: "make", Time: time.Unix(1700000060, 0)},
+	}
+	if got := parseBashHistory(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBashHistory() = %q, want %q", got, want)
+	}
+}
+
+func TestLastHistoryEntries(t *testing.T) {
+	entries := []HistoryEntry{
+		{Command: "cd /srv"},
+		{Command: "make"},
+		{Command: "make"},
+		{Command: "ais 列出文件"},
+		{Command: "make "},
+		{Command: "ais"},
+		{Command: "  "},
+		{Command: "git status"},
+		{Command: "aiscan ."},
+	}
+	tests := []struct {
+		count int
+		want  []HistoryEntry
+	}{
+		{10, []HistoryEntry{{Command: "cd /srv"}, {Command: "make"}, {Command: "git status"}, {Command: "aiscan ."}}},
+		{2, []HistoryEntry{{Command: "git status"}, {Command: "aiscan ."}}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := lastHistoryEntries(entries, tt.count); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lastHistoryEntries(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestReadHistoryFromHistfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("ls\nais 列出文件\npwd\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HISTFILE", path)
+
+	got, err := ReadHistory("/bin/bash", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HistoryEntry{{Command: "ls"}, {Command: "pwd"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadHistory() = %q, want %q", got, want)
+	}
+
+	t.Setenv("HISTFILE", filepath.Join(t.TempDir(), "missing"))
+	if got, err := ReadHistory("/bin/bash", 10); err != nil || got != nil {
+		t.Errorf("ReadHistory(missing) = %q, %v, want nil", got, err)
+	}
+}
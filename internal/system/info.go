@@ -2,10 +2,19 @@ package system
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
+	"os/user"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"AI-Shell/internal/i18n"
 )
 
 // OsInfo 存储系统信息
@@ -15,15 +24,44 @@ type OsInfo struct {
 	PrettyName    string
 }
 
-// GetOsInfo 获取系统信息
+// GetOsInfo 获取系统信息，依次尝试 /etc/os-release、/usr/lib/os-release、/etc/lsb-release，
+// 都不存在时（如精简容器、chroot 和较旧的发行版）使用 uname 的内核信息
 func GetOsInfo() (*OsInfo, error) {
-	file, err := os.Open("/etc/os-release")
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		values, err := readKeyValueFile(path)
+		if err != nil {
+			slog.Debug("读取系统信息失败", "path", path, "error", err)
+			continue
+		}
+		return &OsInfo{
+			DistroID:      values["ID"],
+			DistroVersion: values["VERSION_ID"],
+			PrettyName:    values["PRETTY_NAME"],
+		}, nil
+	}
+
+	values, err := readKeyValueFile("/etc/lsb-release")
+	if err == nil {
+		return &OsInfo{
+			DistroID:      strings.ToLower(values["DISTRIB_ID"]),
+			DistroVersion: values["DISTRIB_RELEASE"],
+			PrettyName:    values["DISTRIB_DESCRIPTION"],
+		}, nil
+	}
+	slog.Debug("读取系统信息失败", "path", "/etc/lsb-release", "error", err)
+
+	return unameOsInfo()
+}
+
+// readKeyValueFile 读取 os-release 格式的 KEY=value 文件
+func readKeyValueFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("读取系统信息失败: %v", err)
+		return nil, i18n.Errorf("读取系统信息失败: %v", err)
 	}
 	defer file.Close()
 
-	info := &OsInfo{}
+	values := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -33,100 +71,180 @@ func GetOsInfo() (*OsInfo, error) {
 		}
 
 		key := parts[0]
-		value := strings.Trim(parts[1], "\"")
-
-		switch key {
-		case "ID":
-			info.DistroID = value
-		case "VERSION_ID":
-			info.DistroVersion = value
-		case "PRETTY_NAME":
-			info.PrettyName = value
-		}
+		value := strings.Trim(parts[1], "\"'")
+		values[key] = value
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("解析系统信息失败: %v", err)
+		return nil, i18n.Errorf("解析系统信息失败: %v", err)
 	}
 
-	return info, nil
+	return values, nil
 }
 
-// GetUserID 获取用户ID信息
+// GetUserID 获取与 id 命令格式相同的用户和组信息，如 uid=1000(alice) gid=1000(alice) groups=1000(alice),27(sudo)。
+// 在 /etc/passwd 或 /etc/group 中查不到的用户和组只显示数字 ID。
 func GetUserID() (string, error) {
-	cmd := exec.Command("id")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("获取用户ID失败: %v", err)
+	uid, gid := os.Getuid(), os.Getgid()
+	if uid < 0 {
+		return "", i18n.Errorf("获取用户ID失败: %v", errors.ErrUnsupported)
 	}
-	return strings.TrimSpace(string(output)), nil
-}
 
-// GetDirectoryInfo 获取目录信息
-func GetDirectoryInfo() (pwd string, files string, err error) {
-	// 获取当前工作目录
-	pwd, err = os.Getwd()
+	username := ""
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	groups, err := os.Getgroups()
 	if err != nil {
-		return "", "", fmt.Errorf("获取当前工作目录失败: %v", err)
+		slog.Debug("获取用户组失败", "error", err)
+	}
+	if !slices.Contains(groups, gid) {
+		groups = append([]int{gid}, groups...)
 	}
 
-	// 获取目录内容
-	entries, err := os.ReadDir(".")
+	groupNames := make([]string, len(groups))
+	for i, group := range groups {
+		groupNames[i] = formatID(group, groupName(group))
+	}
+
+	return fmt.Sprintf("uid=%s gid=%s groups=%s",
+		formatID(uid, username),
+		formatID(gid, groupName(gid)),
+		strings.Join(groupNames, ",")), nil
+}
+
+func groupName(gid int) string {
+	group, err := user.LookupGroupId(strconv.Itoa(gid))
 	if err != nil {
-		return pwd, "", fmt.Errorf("读取目录内容失败: %v", err)
+		return ""
 	}
+	return group.Name
+}
 
-	var filesList []string
-	for _, entry := range entries {
-		name := entry.Name()
-		if entry.IsDir() {
-			name += "/"
-		}
-		filesList = append(filesList, name)
+func formatID(id int, name string) string {
+	if name == "" {
+		return strconv.Itoa(id)
 	}
+	return fmt.Sprintf("%d(%s)", id, name)
+}
+
+// 内置收集器集中在这里注册，输出顺序与注册顺序一致
+func init() {
+	Register(&funcCollector{name: "shell", title: "[echo $SHELL]", collect: collectShell}, Defaults{Enabled: true, Budget: 50})
+	Register(&funcCollector{name: "os", title: "[系统信息]", collect: collectOsInfo}, Defaults{Enabled: true, Budget: 100})
+	Register(&funcCollector{name: "env", title: "[运行环境]", collect: collectEnvironment}, Defaults{Enabled: true, Budget: 150})
+	Register(&funcCollector{name: "hardware", title: "[硬件和资源]", collect: collectHardware}, Defaults{Enabled: true, Budget: 200})
+	Register(&funcCollector{name: "id", title: "[id]", collect: collectUserID}, Defaults{Enabled: true, Budget: 200})
+	Register(&funcCollector{name: "ls", title: "[ls -aF]", collect: collectDirectory}, Defaults{Enabled: true, Budget: 2000})
+	Register(&funcCollector{name: "pwd", title: "[pwd]", collect: collectWorkingDirectory}, Defaults{Enabled: true, Budget: 100})
+	Register(&funcCollector{name: "git", title: "[git]", collect: collectGit}, Defaults{Enabled: true, Budget: 400})
+	// 首次运行时需要探测工具版本，超时时间更长
+	Register(&funcCollector{name: "tools", title: "[可用工具]", collect: collectTools}, Defaults{Enabled: true, Budget: 300, Timeout: 5 * time.Second})
+	// 历史记录可能包含隐私，需要用户主动启用
+	Register(&funcCollector{name: "history", title: "[最近执行的命令]", collect: collectHistory}, Defaults{Enabled: false, Budget: 500})
+}
 
-	files = strings.Join(filesList, " ")
-	return pwd, files, nil
+func collectShell(ctx context.Context, req *Request) (string, error) {
+	return os.Getenv("SHELL"), nil
 }
 
-// GetSystemInfo 获取完整的系统信息
-func GetSystemInfo() (string, error) {
+func collectOsInfo(ctx context.Context, req *Request) (string, error) {
 	osInfo, err := GetOsInfo()
 	if err != nil {
 		return "", err
 	}
+	return fmt.Sprintf("发行版: %s\n发行版ID: %s\n版本: %s",
+		osInfo.PrettyName,
+		osInfo.DistroID,
+		osInfo.DistroVersion), nil
+}
+
+func collectUserID(ctx context.Context, req *Request) (string, error) {
+	return GetUserID()
+}
 
-	userID, err := GetUserID()
+func collectDirectory(ctx context.Context, req *Request) (string, error) {
+	return ListDirectory(".", ListingOptions{
+		MaxEntries: req.Config.Listing.MaxEntries,
+		Tree:       req.Config.Listing.Tree,
+		Details:    WantsDetails(req.Query),
+		Query:      req.Query,
+	})
+}
+
+func collectWorkingDirectory(ctx context.Context, req *Request) (string, error) {
+	pwd, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return "", i18n.Errorf("获取当前工作目录失败: %v", err)
 	}
+	return pwd, nil
+}
 
-	pwd, files, err := GetDirectoryInfo()
-	if err != nil {
-		return "", err
+// GetSystemInfo 并发运行所有已启用的收集器，按注册顺序拼接为完整的系统信息。
+// 收集器失败或超时不会中断，只在对应段落中注明。
+func GetSystemInfo(ctx context.Context, req *Request) (string, error) {
+	start := time.Now()
+
+	var registrations []Registration
+	for _, registration := range Registrations(req.Config) {
+		if enabled, _ := registration.Settings(req.Config); enabled {
+			registrations = append(registrations, registration)
+		}
 	}
 
-	shell := os.Getenv("SHELL")
-
-	info := fmt.Sprintf(`[echo $SHELL]
-%s
-[系统信息]
-发行版: %s
-发行版ID: %s
-版本: %s
-[id]
-%s
-[ls -aF]
-%s
-[pwd]
-%s`,
-		shell,
-		osInfo.PrettyName,
-		osInfo.DistroID,
-		osInfo.DistroVersion,
-		userID,
-		files,
-		pwd)
+	sections := make([]string, len(registrations))
+	var wg sync.WaitGroup
+	for i, registration := range registrations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collector := registration.Collector
+			output, err := runCollector(ctx, req, collector, registration.Timeout(req.Config))
+			if err != nil {
+				// 单个收集器失败时保留其他收集器的结果，并告诉模型这部分信息缺失
+				output = fmt.Sprintf("(获取失败: %v)", err)
+			}
+			if output == "" {
+				return
+			}
+			_, budget := registration.Settings(req.Config)
+			sections[i] = collector.Title() + "\n" + truncateToBudget(output, budget)
+		}()
+	}
+	wg.Wait()
+	slog.Debug("系统信息收集完成", "elapsed", time.Since(start))
 
-	return info, nil
+	sections = slices.DeleteFunc(sections, func(section string) bool { return section == "" })
+	return strings.Join(sections, "\n"), nil
+}
+
+// runCollector 在超时时间内运行收集器。os.ReadDir 等操作不响应 context，
+// 超时后不再等待，收集器的 goroutine 会在后台自行结束。
+func runCollector(ctx context.Context, req *Request, collector Collector, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		output, err := collector.Collect(ctx, req)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		slog.Debug("收集器执行完成", "name", collector.Name(), "elapsed", time.Since(start), "error", r.err)
+		return r.output, r.err
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", ctx.Err()
+		}
+		slog.Debug("收集器超时", "name", collector.Name(), "timeout", timeout)
+		return "", i18n.Errorf("超过 %v 未完成，已跳过", timeout)
+	}
 }
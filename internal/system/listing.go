@@ -0,0 +1,352 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"AI-Shell/internal/i18n"
+)
+
+const (
+	defaultListingMaxEntries = 100 // 条目数超过后改为输出摘要
+	maxListingExtensions     = 10  // 摘要中最多列出的扩展名数
+	maxTreeChildren          = 10  // 目录树中每个子目录最多列出的条目数
+)
+
+// ListingOptions 目录列表的选项
+type ListingOptions struct {
+	MaxEntries int    // 最多列出的条目数，0 表示使用默认值
+	Tree       bool   // 列出深度为 2 的目录树
+	Details    bool   // 显示文件大小和修改时间
+	Query      string // 用户请求，与之相关的条目优先列出
+}
+
+// notableFiles 为对理解目录用途最有帮助的文件，摘要中优先列出
+var notableFiles = map[string]bool{
+	"readme": true, "readme.md": true, "makefile": true, "dockerfile": true, "docker-compose.yml": true,
+	"compose.yaml": true, "package.json": true, "go.mod": true, "cargo.toml": true, "pyproject.toml": true,
+	"requirements.txt": true, "setup.py": true, "pom.xml": true, "build.gradle": true, "cmakelists.txt": true,
+	".gitignore": true, ".env.example": true,
+}
+
+// detailKeywords 出现在请求中时列出文件大小和修改时间。中文关键字使用完整的短语，
+// 避免空间匹配命名空间、时间匹配时间戳、修改匹配修改配置
+var detailKeywords = newKeywordMatcher(
+	[]string{"文件大小", "按大小", "大小超过", "大于", "小于", "最大", "最小", "占用空间", "空间占用", "体积",
+		"最近修改", "最近更新", "最近创建", "修改时间", "创建时间", "访问时间", "按时间", "修改过", "最新的文件", "最旧", "日期", "天前"},
+	[]string{`sizes?`, `large(r|st)?`, `big(ger|gest)?`, `small(er|est)?`, `\d*(k|m|g)i?b`, `space`, `recent\w*`,
+		`modified`, `newest`, `oldest`, `older`, `newer`, `age`, `dates?`, `mtime`},
+)
+
+// WantsDetails 判断请求是否与文件大小或时间有关
+func WantsDetails(query string) bool {
+	return detailKeywords.match(query)
+}
+
+// ListDirectory 列出目录内容。条目较少时与 ls -aF 类似，
+// 条目过多时输出按类型和扩展名的统计，并只列出相关的和抽样的部分条目。
+func ListDirectory(dir string, opts ListingOptions) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", i18n.Errorf("读取目录内容失败: %v", err)
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultListingMaxEntries
+	}
+
+	switch {
+	case len(entries) > opts.MaxEntries:
+		return summarizeEntries(dir, entries, opts), nil
+	case opts.Tree:
+		return listTree(dir, entries, opts), nil
+	default:
+		return formatEntries(dir, entries, opts.Details), nil
+	}
+}
+
+// formatEntries 格式化条目，显示详情时每行一个条目
+func formatEntries(dir string, entries []os.DirEntry, details bool) string {
+	labels := make([]string, len(entries))
+	for i, entry := range entries {
+		labels[i] = entryLabel(dir, entry, details)
+	}
+	if details {
+		return strings.Join(labels, "\n")
+	}
+	return strings.Join(labels, " ")
+}
+
+// entryLabel 返回条目名称，目录以 / 结尾，显示详情时附带大小和修改时间
+func entryLabel(dir string, entry os.DirEntry, details bool) string {
+	name := entry.Name()
+	if entry.IsDir() {
+		name += "/"
+	}
+	if !details {
+		return name
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return name
+	}
+	size := "-"
+	if !entry.IsDir() {
+		size = formatSize(info.Size())
+	}
+	return fmt.Sprintf("%s  %6s  %s", info.ModTime().Format("2006-01-02 15:04"), size, name)
+}
+
+// formatSize 以 ls -h 的风格格式化文件大小
+func formatSize(size int64) string {
+	const units = "KMGTP"
+	if size < 1024 {
+		return fmt.Sprintf("%d", size)
+	}
+	value := float64(size)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, units[unit])
+	}
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
+
+// summarizeEntries 输出按类型和扩展名的统计，以及最多 MaxEntries 个条目
+func summarizeEntries(dir string, entries []os.DirEntry, opts ListingOptions) string {
+	var dirs, files, links, others int
+	extensions := make(map[string]int)
+	for _, entry := range entries {
+		switch mode := entry.Type(); {
+		case mode.IsDir():
+			dirs++
+		case mode&fs.ModeSymlink != 0:
+			links++
+		case mode.IsRegular():
+			files++
+			extensions[extensionOf(entry.Name())]++
+		default:
+			others++
+		}
+	}
+
+	lines := []string{fmt.Sprintf("共 %d 项: %d 个目录, %d 个文件, %d 个符号链接, %d 个其他",
+		len(entries), dirs, files, links, others)}
+	if len(extensions) > 0 {
+		lines = append(lines, "扩展名: "+formatExtensions(extensions))
+	}
+
+	selected := selectEntries(entries, opts.Query, opts.MaxEntries)
+	lines = append(lines, "部分条目:", formatEntries(dir, selected, opts.Details))
+	lines = append(lines, fmt.Sprintf("...省略 %d 项", len(entries)-len(selected)))
+	return strings.Join(lines, "\n")
+}
+
+func extensionOf(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" || ext == name {
+		return "(无扩展名)"
+	}
+	return ext
+}
+
+// formatExtensions 按数量从多到少列出扩展名
+func formatExtensions(extensions map[string]int) string {
+	names := make([]string, 0, len(extensions))
+	for ext := range extensions {
+		names = append(names, ext)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if extensions[names[i]] != extensions[names[j]] {
+			return extensions[names[i]] > extensions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var parts []string
+	for i, ext := range names {
+		if i == maxListingExtensions {
+			parts = append(parts, fmt.Sprintf("其他 %d 种", len(names)-i))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", ext, extensions[ext]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectEntries 按与请求相关、常见项目文件、目录、其他条目的顺序选出最多 limit 个条目，
+// 目录和其他条目超出剩余名额时均匀抽样
+func selectEntries(entries []os.DirEntry, query string, limit int) []os.DirEntry {
+	words := queryWords(query)
+	var matched, notable, dirs, rest []os.DirEntry
+	for _, entry := range entries {
+		switch {
+		case matchesQuery(entry.Name(), words):
+			matched = append(matched, entry)
+		case notableFiles[strings.ToLower(entry.Name())]:
+			notable = append(notable, entry)
+		case entry.IsDir():
+			dirs = append(dirs, entry)
+		default:
+			rest = append(rest, entry)
+		}
+	}
+
+	var selected []os.DirEntry
+	for _, group := range [][]os.DirEntry{matched, notable, dirs, rest} {
+		selected = append(selected, sample(group, limit-len(selected))...)
+	}
+	return selected
+}
+
+// sample 从 entries 中均匀抽取最多 count 个条目
+func sample(entries []os.DirEntry, count int) []os.DirEntry {
+	if count <= 0 {
+		return nil
+	}
+	if len(entries) <= count {
+		return entries
+	}
+	sampled := make([]os.DirEntry, count)
+	for i := range sampled {
+		sampled[i] = entries[i*len(entries)/count]
+	}
+	return sampled
+}
+
+// queryWords 将请求拆分为小写的单词，用于匹配文件名
+func queryWords(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_' && r != '-'
+	})
+}
+
+// matchesQuery 判断文件名是否包含请求中的单词，或扩展名与请求中的单词相同
+func matchesQuery(name string, words []string) bool {
+	name = strings.ToLower(name)
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	for _, word := range words {
+		word = strings.Trim(word, ".")
+		if (len(word) >= 3 && strings.Contains(name, word)) || (word != "" && word == ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// listTree 列出深度为 2 的目录树，跳过 .git 和 .gitignore 中忽略的文件
+func listTree(dir string, entries []os.DirEntry, opts ListingOptions) string {
+	ignore := loadGitignore(dir, "")
+	var lines []string
+	for _, entry := range entries {
+		if entry.Name() == ".git" || ignore.match(entry.Name(), entry.IsDir()) {
+			continue
+		}
+		lines = append(lines, entryLabel(dir, entry, opts.Details))
+		if !entry.IsDir() || len(lines) >= opts.MaxEntries {
+			continue
+		}
+
+		subDir := filepath.Join(dir, entry.Name())
+		children, err := os.ReadDir(subDir)
+		if err != nil {
+			continue
+		}
+		childIgnore := ignore.with(loadGitignore(subDir, entry.Name()))
+
+		var visible []os.DirEntry
+		for _, child := range children {
+			if !childIgnore.match(path.Join(entry.Name(), child.Name()), child.IsDir()) {
+				visible = append(visible, child)
+			}
+		}
+		limit := min(maxTreeChildren, opts.MaxEntries-len(lines))
+		shown := selectEntries(visible, opts.Query, limit)
+		for _, child := range shown {
+			lines = append(lines, "  "+entryLabel(subDir, child, opts.Details))
+		}
+		if len(visible) > len(shown) {
+			lines = append(lines, fmt.Sprintf("  ...省略 %d 项", len(visible)-len(shown)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// gitignoreRule 为 .gitignore 中的一条规则
+type gitignoreRule struct {
+	pattern  string
+	base     string // 规则所在 .gitignore 相对于列表目录的路径
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// gitignore 只支持常用的语法：通配符、! 取反、/ 结尾匹配目录、包含 / 时相对 .gitignore 所在目录匹配
+type gitignore []gitignoreRule
+
+// loadGitignore 读取 dir 中的 .gitignore，base 为 dir 相对于列表目录的路径
+func loadGitignore(dir, base string) gitignore {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules gitignore
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := gitignoreRule{base: base}
+		if rule.negate = strings.HasPrefix(line, "!"); rule.negate {
+			line = line[1:]
+		}
+		if rule.dirOnly = strings.HasSuffix(line, "/"); rule.dirOnly {
+			line = strings.TrimSuffix(line, "/")
+		}
+		line = strings.TrimPrefix(line, "**/")
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// with 返回追加了子目录规则后的规则列表，子目录的规则优先
+func (g gitignore) with(rules gitignore) gitignore {
+	return append(append(gitignore(nil), g...), rules...)
+}
+
+// match 判断相对于列表目录的路径是否被忽略，后面的规则优先
+func (g gitignore) match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range g {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := path.Base(relPath)
+		if rule.anchored {
+			rel, ok := strings.CutPrefix(relPath, rule.base)
+			if !ok {
+				continue
+			}
+			target = strings.TrimPrefix(rel, "/")
+		}
+		if matched, _ := path.Match(rule.pattern, target); matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
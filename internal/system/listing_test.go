@@ -0,0 +1,35 @@
+package system
+
+import "testing"
+
+func TestWantsDetails(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"find files larger than 100MB", true},
+		{"delete logs older than 7 days", true},
+		{"sort by date", true},
+		{"show the 10 most recently modified files", true},
+		{"列出最大的 5 个文件", true},
+		{"按修改时间排序", true},
+		{"找出最近修改的配置文件", true},
+		{"哪个目录占用空间最多", true},
+		{"update the submodules", false},
+		{"build the docker image", false},
+		{"install the package", false},
+		{"print the commit message", false},
+		{"count lines by number", false},
+		{"删除 k8s 命名空间", false},
+		{"开启时间同步", false},
+		{"打印当前的时间戳", false},
+		{"最近在做的项目用什么构建", false},
+		{"修改 nginx 配置文件的端口", false},
+		{"忽略大小写搜索 error", false},
+	}
+	for _, tt := range tests {
+		if got := WantsDetails(tt.query); got != tt.want {
+			t.Errorf("WantsDetails(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
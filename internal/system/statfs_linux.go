@@ -0,0 +1,14 @@
+//go:build linux
+
+package system
+
+import "syscall"
+
+// statFS 返回 path 所在文件系统的总容量和非特权用户可用的容量
+func statFS(path string) (total, available int64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize), int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package system
+
+import "errors"
+
+// statFS 在非 Linux 系统上不支持
+func statFS(path string) (total, available int64, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
@@ -0,0 +1,397 @@
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"AI-Shell/internal/config"
+)
+
+const (
+	toolsCacheFile     = "tools_cache.json"
+	toolsCacheTTL      = 24 * time.Hour
+	toolVersionTimeout = 500 * time.Millisecond
+)
+
+// commonTools 为模型经常建议的工具，只探测这些工具是否存在
+var commonTools = []string{
+	"fd", "fdfind", "rg", "ag", "jq", "yq", "fzf", "bat", "batcat", "eza", "exa", "tree",
+	"htop", "btop", "ncdu", "duf", "curl", "wget", "rsync", "ssh", "tmux", "screen",
+	"git", "make", "gcc", "go", "cargo", "python3", "python", "pip3", "node", "npm", "pnpm", "yarn", "java",
+	"docker", "docker-compose", "podman", "kubectl", "helm",
+	"gawk", "parallel", "zip", "unzip", "7z", "xz", "zstd", "ffmpeg", "convert", "sqlite3", "psql", "mysql", "redis-cli",
+	"nc", "nmap", "dig", "ip", "ss", "netstat", "lsof", "strace",
+	"systemctl", "journalctl", "service", "crontab", "sudo", "doas",
+}
+
+// versionCommands 为可以低成本获取版本号的工具及其参数
+var versionCommands = map[string][]string{
+	"git":     {"--version"},
+	"go":      {"version"},
+	"python3": {"--version"},
+	"node":    {"--version"},
+	"docker":  {"--version"},
+	"kubectl": {"version", "--client"},
+}
+
+// packageManagers 按常见程度排列
+var packageManagers = []string{"apt", "dnf", "yum", "pacman", "apk", "zypper", "emerge", "brew", "nix", "snap", "flatpak"}
+
+// dockerComposePluginDirs 为 docker compose 插件的常见安装位置
+var dockerComposePluginDirs = []string{
+	"~/.docker/cli-plugins",
+	"/usr/local/lib/docker/cli-plugins",
+	"/usr/local/libexec/docker/cli-plugins",
+	"/usr/lib/docker/cli-plugins",
+	"/usr/libexec/docker/cli-plugins",
+}
+
+// ToolInventory 存储已安装工具的探测结果
+type ToolInventory struct {
+	Created         time.Time         `json:"created"`
+	PathHash        string            `json:"path_hash"` // $PATH 变化时缓存失效
+	Available       []string          `json:"available"`
+	Missing         []string          `json:"missing"`
+	Versions        map[string]string `json:"versions,omitempty"`
+	PackageManagers []string          `json:"package_managers,omitempty"`
+}
+
+func collectTools(ctx context.Context, req *Request) (string, error) {
+	inventory := LoadToolInventory(ctx)
+	return inventory.String(), nil
+}
+
+// String 将探测结果格式化为发送给模型的文本
+func (t *ToolInventory) String() string {
+	available := make([]string, len(t.Available))
+	for i, tool := range t.Available {
+		available[i] = tool
+		if version, ok := t.Versions[tool]; ok {
+			available[i] += "(" + version + ")"
+		}
+	}
+
+	lines := []string{
+		"已安装: " + strings.Join(available, " "),
+		"未安装: " + strings.Join(t.Missing, " "),
+	}
+	if len(t.PackageManagers) > 0 {
+		lines = append(lines, "包管理器: "+strings.Join(t.PackageManagers, " "))
+	}
+	lines = append(lines, "不要使用未安装的工具；ls、grep、sed、awk、find 等基本的 POSIX 工具总是可用")
+	return strings.Join(lines, "\n")
+}
+
+// LoadToolInventory 读取缓存的探测结果，缓存过期或 $PATH 变化时重新探测
+func LoadToolInventory(ctx context.Context) *ToolInventory {
+	cachePath := filepath.Join(config.Dir(), toolsCacheFile)
+	pathHash := hashPath(os.Getenv("PATH"))
+
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cached ToolInventory
+		if err := json.Unmarshal(data, &cached); err == nil &&
+			cached.PathHash == pathHash && time.Since(cached.Created) < toolsCacheTTL {
+			slog.Debug("使用缓存的工具探测结果", "path", cachePath)
+			return &cached
+		}
+	}
+
+	inventory := probeTools(ctx)
+	inventory.PathHash = pathHash
+	if err := saveToolInventory(cachePath, inventory); err != nil {
+		slog.Debug("保存工具探测结果失败", "error", err)
+	}
+	return inventory
+}
+
+func saveToolInventory(path string, inventory *ToolInventory) error {
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func hashPath(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:8])
+}
+
+// probeTools 在 $PATH 中探测常用工具和包管理器
+func probeTools(ctx context.Context) *ToolInventory {
+	inventory := &ToolInventory{Created: time.Now(), Versions: make(map[string]string)}
+
+	for _, tool := range commonTools {
+		if _, err := exec.LookPath(tool); err != nil {
+			inventory.Missing = append(inventory.Missing, tool)
+			continue
+		}
+		inventory.Available = append(inventory.Available, tool)
+
+		if args, ok := versionCommands[tool]; ok {
+			if version := probeVersion(ctx, tool, args); version != "" {
+				inventory.Versions[tool] = version
+			}
+		}
+	}
+
+	if hasDockerComposePlugin() {
+		inventory.Available = append(inventory.Available, "docker compose")
+	} else if _, err := exec.LookPath("docker"); err == nil {
+		inventory.Missing = append(inventory.Missing, "docker compose")
+	}
+
+	for _, manager := range packageManagers {
+		if _, err := exec.LookPath(manager); err == nil {
+			inventory.PackageManagers = append(inventory.PackageManagers, manager)
+		}
+	}
+
+	return inventory
+}
+
+// probeVersion 运行工具的版本命令，从第一行输出中提取版本号
+func probeVersion(ctx context.Context, tool string, args []string) string {
+	ctx, cancel := context.WithTimeout(ctx, toolVersionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, tool, args...).Output()
+	if err != nil {
+		return ""
+	}
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	for _, field := range strings.Fields(firstLine) {
+		field = strings.TrimPrefix(strings.TrimSuffix(field, ","), "go")
+		field = strings.TrimPrefix(field, "v")
+		if field != "" && field[0] >= '0' && field[0] <= '9' && strings.Contains(field, ".") {
+			return field
+		}
+	}
+	return ""
+}
+
+func hasDockerComposePlugin() bool {
+	home, _ := os.UserHomeDir()
+	for _, dir := range dockerComposePluginDirs {
+		dir = strings.Replace(dir, "~", home, 1)
+		if _, err := os.Stat(filepath.Join(dir, "docker-compose")); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// shellBuiltins 为不需要在 $PATH 中存在的 shell 内建命令和关键字
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "printf": true, "export": true, "unset": true, "set": true, "source": true, ".": true,
+	"alias": true, "type": true, "command": true, "builtin": true, "exec": true, "eval": true, "read": true,
+	"test": true, "[": true, "[[": true, "true": true, "false": true, ":": true, "exit": true, "return": true,
+	"if": true, "then": true, "else": true, "elif": true, "fi": true, "for": true, "while": true, "until": true,
+	"do": true, "done": true, "case": true, "esac": true, "in": true, "function": true, "{": true, "}": true,
+	"(": true, ")": true, "!": true, "local": true, "declare": true, "history": true, "jobs": true, "fg": true,
+	"bg": true, "wait": true, "kill": true, "trap": true, "ulimit": true, "umask": true, "pushd": true, "popd": true,
+	"time": true, // bash 和 zsh 的关键字，系统中不一定有 /usr/bin/time
+}
+
+// commandPrefixes 为包装其他命令的前缀，需要检查其后的命令
+var commandPrefixes = map[string]bool{
+	"sudo": true, "doas": true, "env": true, "time": true, "nohup": true, "nice": true, "xargs": true, "watch": true,
+}
+
+// MissingCommands 返回命令中用到但 $PATH 中不存在的可执行文件。
+// 只做粗略的词法分析，复杂的 shell 语法可能漏报，但不会把内建命令误报为缺失。
+func MissingCommands(command string) []string {
+	var missing []string
+	seen := make(map[string]bool)
+
+	for _, words := range commandSegments(command) {
+		prefixes, fields := skipCommandPrefixes(stripRedirections(words))
+		names := prefixes
+		if len(fields) > 0 {
+			names = append(names, strings.Trim(fields[0], "(){}"))
+		}
+
+		for _, name := range names {
+			if name == "" || shellBuiltins[name] || seen[name] || strings.ContainsAny(name, "$/`") {
+				continue
+			}
+			seen[name] = true
+			if _, err := exec.LookPath(name); err != nil {
+				missing = append(missing, name)
+			}
+		}
+
+		// docker 本身已安装时，还需要检查 compose 插件
+		if len(fields) > 1 && fields[0] == "docker" && fields[1] == "compose" &&
+			!slices.Contains(missing, "docker") && !seen["docker compose"] && !hasDockerComposePlugin() {
+			seen["docker compose"] = true
+			missing = append(missing, "docker compose")
+		}
+	}
+	return missing
+}
+
+// prefixFlagsWithValue 为命令前缀中带参数值的选项，如 sudo -u root、xargs -I {}
+var prefixFlagsWithValue = map[string]bool{
+	"-u": true, "-g": true, "-C": true, "-p": true, "-I": true, "-n": true, "-P": true, "-d": true, "-L": true,
+}
+
+// skipCommandPrefixes 跳过环境变量赋值和 sudo、xargs 之类的命令前缀及其选项，
+// 返回遇到的命令前缀和剩余的字段
+func skipCommandPrefixes(fields []string) (prefixes []string, rest []string) {
+	inPrefix := false
+	for len(fields) > 0 {
+		field := fields[0]
+		switch {
+		case commandPrefixes[field]:
+			inPrefix = true
+			prefixes = append(prefixes, field)
+		case strings.Contains(field, "=") && !strings.HasPrefix(field, "-"):
+		case inPrefix && strings.HasPrefix(field, "-"):
+			if prefixFlagsWithValue[field] && len(fields) > 1 {
+				fields = fields[1:]
+			}
+		default:
+			return prefixes, fields
+		}
+		fields = fields[1:]
+	}
+	return prefixes, fields
+}
+
+// commandSegments 将命令拆分为简单命令，每个简单命令为一组单词。
+// 按管道、逻辑运算符、分号、后台运行符和命令替换拆分，引号中的内容不拆分，作为单词的一部分，
+// 重定向中的 & 如 2>&1、&> log 不作为后台运行符。命令替换在外层命令中替换为 $()
+func commandSegments(command string) [][]string {
+	var (
+		segments [][]string
+		outer    [][]string // 命令替换外层命令已读取的单词
+		words    []string
+		word     strings.Builder
+		inWord   bool
+		backtick bool
+	)
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endSegment := func() {
+		endWord()
+		if len(words) > 0 {
+			segments = append(segments, words)
+		}
+		words = nil
+	}
+	openSubst := func() {
+		endWord()
+		outer = append(outer, words)
+		words = nil
+	}
+	closeSubst := func() {
+		endSegment()
+		words = append(outer[len(outer)-1], "$()")
+		outer = outer[:len(outer)-1]
+	}
+
+	runes := []rune(command)
+	next := func(i int) rune {
+		if i+1 < len(runes) {
+			return runes[i+1]
+		}
+		return 0
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			word.WriteString(string(runes[i+1 : min(end, len(runes))]))
+			inWord, i = true, end
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			word.WriteString(string(runes[i+1 : min(end, len(runes))]))
+			inWord, i = true, end
+		case r == '$' && next(i) == '(':
+			openSubst()
+			i++
+		case r == ')' && len(outer) > 0 && !backtick:
+			closeSubst()
+		case r == '`':
+			if backtick {
+				closeSubst()
+			} else {
+				openSubst()
+			}
+			backtick = !backtick
+		case r == '&' && next(i) == '&':
+			endSegment()
+			i++
+		case r == '&' && (next(i) == '>' || strings.HasSuffix(word.String(), ">") || strings.HasSuffix(word.String(), "<")):
+			word.WriteRune(r)
+			inWord = true
+		case r == '&' || r == '|' || r == ';' || r == '\n':
+			endSegment()
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	endSegment()
+	// 未闭合的命令替换
+	for len(outer) > 0 {
+		closeSubst()
+		endSegment()
+	}
+	return segments
+}
+
+// redirectionPattern 匹配重定向运算符，如 >、2>>、2>&1、&>、<<EOF
+var redirectionPattern = regexp.MustCompile(`^(\d+|&)?(>>|>&|>\||>|<<<|<<-?|<&|<>|<)`)
+
+// stripRedirections 去掉重定向及其目标，目标与运算符分开书写时一并去掉
+func stripRedirections(words []string) []string {
+	var rest []string
+	for i := 0; i < len(words); i++ {
+		operator := redirectionPattern.FindString(words[i])
+		if operator == "" {
+			rest = append(rest, words[i])
+			continue
+		}
+		if operator == words[i] {
+			i++
+		}
+	}
+	return rest
+}
@@ -0,0 +1,70 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandSegments(t *testing.T) {
+	tests := []struct {
+		command string
+		want    [][]string
+	}{
+		{"make 2>&1 | tee build.log", [][]string{{"make", "2>&1"}, {"tee", "build.log"}}},
+		{`grep -E "error|warn" app.log`, [][]string{{"grep", "-E", "error|warn", "app.log"}}},
+		{`awk 'BEGIN{n=0; print n}' f`, [][]string{{"awk", "BEGIN{n=0; print n}", "f"}}},
+		{"make &> build.log && ls", [][]string{{"make", "&>", "build.log"}, {"ls"}}},
+		{"sleep 10 & ls; pwd", [][]string{{"sleep", "10"}, {"ls"}, {"pwd"}}},
+		{"echo $(date +%F) done", [][]string{{"date", "+%F"}, {"echo", "$()", "done"}}},
+		{"echo `whoami` done", [][]string{{"whoami"}, {"echo", "$()", "done"}}},
+		{`echo a\;b`, [][]string{{"echo", "a;b"}}},
+	}
+	for _, tt := range tests {
+		if got := commandSegments(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("commandSegments(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestMissingCommands(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"make", "tee", "grep", "awk", "ls", "sudo", "date"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"make 2>&1 | tee build.log", nil},
+		{`grep -E "error|warn" app.log`, nil},
+		{`awk 'BEGIN{n=0; print n}' f`, nil},
+		{"time ls", nil},
+		{"make &> build.log", nil},
+		{"ls 2> /dev/null", nil},
+		{"sudo -u root fd -e go", []string{"fd"}},
+		{"LANG=C rg foo | jq .", []string{"rg", "jq"}},
+		{"echo $(date) && bat README.md", []string{"bat"}},
+	}
+	for _, tt := range tests {
+		if got := MissingCommands(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MissingCommands(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestToolInventoryStringAllowsBasicTools(t *testing.T) {
+	inventory := &ToolInventory{Available: []string{"git"}, Missing: []string{"rg"}, Versions: map[string]string{"git": "2.39.5"}}
+	got := inventory.String()
+	for _, want := range []string{"已安装: git(2.39.5)", "未安装: rg", "不要使用未安装的工具", "POSIX 工具总是可用"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package system
+
+import (
+	"strings"
+	"syscall"
+
+	"AI-Shell/internal/i18n"
+)
+
+// unameOsInfo 通过 uname 系统调用获取内核名称和版本
+func unameOsInfo() (*OsInfo, error) {
+	var uts syscall.Utsname
+	if err := syscall.Uname(&uts); err != nil {
+		return nil, i18n.Errorf("读取系统信息失败: %v", err)
+	}
+	sysname, release := utsString(uts.Sysname), utsString(uts.Release)
+	return &OsInfo{
+		DistroID:      strings.ToLower(sysname),
+		DistroVersion: release,
+		PrettyName:    sysname + " " + release + " (未知发行版)",
+	}, nil
+}
+
+// utsString 将 Utsname 中以 0 结尾的字符数组转换为字符串，不同架构上数组元素分别为 int8 或 uint8
+func utsString[T int8 | uint8](chars [65]T) string {
+	var builder strings.Builder
+	for _, c := range chars {
+		if c == 0 {
+			break
+		}
+		builder.WriteByte(byte(c))
+	}
+	return builder.String()
+}
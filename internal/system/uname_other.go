@@ -0,0 +1,13 @@
+//go:build !linux
+
+package system
+
+import "runtime"
+
+// unameOsInfo 在非 Linux 系统上只能提供操作系统名称
+func unameOsInfo() (*OsInfo, error) {
+	return &OsInfo{
+		DistroID:   runtime.GOOS,
+		PrettyName: runtime.GOOS,
+	}, nil
+}
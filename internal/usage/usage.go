@@ -0,0 +1,159 @@
+// Package usage 在本地记录每次请求的令牌用量和费用，按日期、模型和 API 地址汇总
+package usage
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"AI-Shell/internal/config"
+	"AI-Shell/internal/i18n"
+)
+
+const usageFile = "usage.json"
+
+// Record 为某一天在某个 API 地址上使用某个模型的汇总
+type Record struct {
+	Date             string  `json:"date"` // 本地日期，格式为 2006-01-02
+	Model            string  `json:"model"`
+	Endpoint         string  `json:"endpoint"` // API 地址的主机名，不包含路径和查询参数
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`               // 按记录时的价格计算
+	Unpriced         int     `json:"unpriced,omitempty"` // 记录时没有设置价格的请求数
+}
+
+// Store 为用量记录文件的内容
+type Store struct {
+	Records []Record `json:"records"`
+	path    string
+}
+
+// Path 返回用量记录文件的路径
+func Path() string {
+	return filepath.Join(config.Dir(), usageFile)
+}
+
+// Load 读取用量记录，文件不存在时返回空记录
+func Load() (*Store, error) {
+	store := &Store{path: Path()}
+	data, err := os.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("读取用量记录失败: %v", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, i18n.Errorf("解析用量记录失败: %v", err)
+	}
+	return store, nil
+}
+
+// Save 保存用量记录，先写入临时文件再重命名，避免中断时损坏记录
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return i18n.Errorf("序列化用量记录失败: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return i18n.Errorf("保存用量记录失败: %v", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return i18n.Errorf("保存用量记录失败: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return i18n.Errorf("保存用量记录失败: %v", err)
+	}
+	return nil
+}
+
+// Add 将一次请求的用量累加到对应日期、模型和 API 地址的记录中，priced 为 false 表示没有设置价格
+func (s *Store) Add(now time.Time, model, endpoint string, promptTokens, completionTokens int, cost float64, priced bool) {
+	date := now.Format(time.DateOnly)
+	index := -1
+	for i, record := range s.Records {
+		if record.Date == date && record.Model == model && record.Endpoint == endpoint {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		s.Records = append(s.Records, Record{Date: date, Model: model, Endpoint: endpoint})
+		index = len(s.Records) - 1
+	}
+
+	record := &s.Records[index]
+	record.Requests++
+	record.PromptTokens += promptTokens
+	record.CompletionTokens += completionTokens
+	record.Cost += cost
+	if !priced {
+		record.Unpriced++
+	}
+}
+
+// Month 返回 month 所在月份的记录，按日期和模型排序
+func (s *Store) Month(month time.Time) []Record {
+	prefix := month.Format("2006-01")
+	var records []Record
+	for _, record := range s.Records {
+		if strings.HasPrefix(record.Date, prefix) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Date != records[j].Date {
+			return records[i].Date < records[j].Date
+		}
+		if records[i].Model != records[j].Model {
+			return records[i].Model < records[j].Model
+		}
+		return records[i].Endpoint < records[j].Endpoint
+	})
+	return records
+}
+
+// MonthCost 返回 month 所在月份的总费用
+func (s *Store) MonthCost(month time.Time) float64 {
+	var cost float64
+	for _, record := range s.Month(month) {
+		cost += record.Cost
+	}
+	return cost
+}
+
+// Endpoint 返回 API 地址的主机名，用于区分不同的服务
+func Endpoint(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return apiURL
+	}
+	return u.Host
+}
+
+// Track 按配置中的价格表记录一次发送到 apiURL 的请求的用量，返回记录后本月的总费用。
+// 使用备用模型时 apiURL 和 model 为实际回答请求的地址和模型
+func Track(cfg *config.Config, apiURL, model string, promptTokens, completionTokens int) (float64, error) {
+	store, err := Load()
+	if err != nil {
+		return 0, err
+	}
+	cost, priced := cfg.Usage.Cost(model, promptTokens, completionTokens)
+	if !priced {
+		slog.Debug("模型没有设置价格，费用按 0 记录", "model", model)
+	}
+	now := time.Now()
+	store.Add(now, model, Endpoint(apiURL), promptTokens, completionTokens, cost, priced)
+	if err := store.Save(); err != nil {
+		return 0, err
+	}
+	return store.MonthCost(now), nil
+}
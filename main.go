@@ -5,11 +5,13 @@ import (
 	"os"
 
 	"AI-Shell/cmd"
+
+	"AI-Shell/internal/i18n"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "执行错误: %v\n", err)
+		fmt.Fprintf(os.Stderr, i18n.T("执行错误: %v\n"), err)
 		os.Exit(1)
 	}
 }